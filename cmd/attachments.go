@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lunchboxsushi/jai/internal/convert"
+	"github.com/lunchboxsushi/jai/internal/jira"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// uploadAttachments uploads the local files linked from a ticket's description that
+// haven't been attached yet, resolving relative paths against baseDir. Uploaded files are
+// recorded on the ticket, and the number of new attachments is returned.
+func uploadAttachments(jiraClient *jira.Client, ticket *types.Ticket, baseDir string) int {
+	uploaded := 0
+	for _, target := range convert.LocalFileLinks(ticket.Description) {
+		if attachmentFor(ticket, target) != nil {
+			continue
+		}
+
+		filePath := expandHome(target)
+		if !filepath.IsAbs(filePath) {
+			filePath = filepath.Join(baseDir, filePath)
+		}
+		if info, err := os.Stat(filePath); err != nil || info.IsDir() {
+			fmt.Printf("Warning: %s links to %s, which is not a file\n", ticket.Key, target)
+			continue
+		}
+
+		attachment, err := jiraClient.UploadAttachment(ticket.Key, target, filePath)
+		if err != nil {
+			fmt.Printf("Warning: %s\n", describeError(err))
+			continue
+		}
+		ticket.Attachments = append(ticket.Attachments, *attachment)
+		uploaded++
+		fmt.Printf("📎 %s: attached %s\n", ticket.Key, target)
+	}
+	return uploaded
+}
+
+// attachmentFor returns the attachment already uploaded for a link target, if any
+func attachmentFor(ticket *types.Ticket, target string) *types.Attachment {
+	for i, attachment := range ticket.Attachments {
+		if attachment.Path == target {
+			return &ticket.Attachments[i]
+		}
+	}
+	return nil
+}
+
+// attachAfterCreate uploads the files linked from a newly created ticket and points the
+// description in Jira at them
+func attachAfterCreate(jiraClient *jira.Client, ticket *types.Ticket) {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return
+	}
+	if uploadAttachments(jiraClient, ticket, filepath.Join(dataDir, "tickets")) == 0 {
+		return
+	}
+	if err := jiraClient.UpdateTicket(ticket); err != nil {
+		fmt.Printf("Warning: Failed to link attachments in the description: %s\n", describeError(err))
+	}
+}
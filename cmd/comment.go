@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var commentCmd = &cobra.Command{
+	Use:   "comment [key]",
+	Short: "Post a comment on the focused ticket",
+	Long: `Post a comment on the focused ticket (or the given key) in Jira. Without -m an
+editor is opened for drafting the comment. After posting, the ticket's comments
+section in its markdown file is refreshed from Jira.
+
+Examples:
+  jai comment                          # Draft a comment in your editor
+  jai comment -m "Deployed to staging" # Comment on the focused ticket
+  jai comment SRE-1234 -m "LGTM"       # Comment on a specific ticket`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runComment,
+}
+
+var commentMessage string
+
+func init() {
+	commentCmd.Flags().StringVarP(&commentMessage, "message", "m", "", "Comment text (opens an editor when omitted)")
+	rootCmd.AddCommand(commentCmd)
+}
+
+func runComment(cmd *cobra.Command, args []string) error {
+	if err := requireJira("comment"); err != nil {
+		return err
+	}
+
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
+	}
+
+	key, err := resolveTicketKey(dataDir, args)
+	if err != nil {
+		return err
+	}
+
+	body := commentMessage
+	if body == "" {
+		body, err = openEditorWithTemplate("jai-comment-*.md", "")
+		if err != nil {
+			return fmt.Errorf("failed to open editor: %w", err)
+		}
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		fmt.Println("No content provided, comment cancelled")
+		return nil
+	}
+
+	jiraClient, err := newKeyClient(key)
+	if err != nil {
+		return err
+	}
+
+	if _, err := jiraClient.AddComment(key, body); err != nil {
+		return err
+	}
+	fmt.Printf("Comment posted on %s\n", key)
+
+	// Refresh the local comments section so it matches Jira
+	parser := newParser(dataDir)
+	filePath, ticket, err := findTicketByKey(dataDir, parser, key)
+	if err != nil {
+		fmt.Printf("Warning: %v, local comments not updated\n", err)
+		return nil
+	}
+
+	comments, err := jiraClient.GetComments(key)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		return nil
+	}
+
+	ticket.Comments = comments
+	if err := parser.UpdateTicket(filePath, *ticket); err != nil {
+		fmt.Printf("Warning: Failed to update comments in %s: %v\n", filePath, err)
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/lunchboxsushi/jai/internal/jira"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
@@ -19,6 +20,7 @@ var configCmd = &cobra.Command{
 Examples:
   jai config init              # Initialize configuration
   jai config show              # Show current configuration
+  jai config detect            # Detect Jira field IDs and save them
   jai config set jira.url https://company.atlassian.net`,
 	RunE: runConfig,
 }
@@ -37,6 +39,8 @@ func runConfig(cmd *cobra.Command, args []string) error {
 		return initConfigCmd()
 	case "show":
 		return showConfig()
+	case "detect":
+		return detectConfig()
 	case "set":
 		if len(args) < 3 {
 			return fmt.Errorf("usage: jai config set <key> <value>")
@@ -111,18 +115,30 @@ func showConfig() error {
 	fmt.Printf("Configuration file: %s\n\n", configPath)
 
 	// Show Jira config
-	fmt.Println("Jira Configuration:")
+	if profile := activeProfile(); profile != "" {
+		fmt.Printf("Jira Configuration (profile %s):\n", profile)
+	} else {
+		fmt.Println("Jira Configuration:")
+	}
 	fmt.Printf("  URL: %s\n", viper.GetString("jira.url"))
 	fmt.Printf("  Username: %s\n", viper.GetString("jira.username"))
+	fmt.Printf("  Auth: %s\n", jira.AuthMode(loadJiraConfig()))
 	fmt.Printf("  Project: %s\n", viper.GetString("jira.project"))
+	fmt.Printf("  Epic Link Field: %s\n", viper.GetString("jira.epic_link_field"))
+	if boardID := viper.GetInt("jira.board_id"); boardID != 0 {
+		fmt.Printf("  Board: %d\n", boardID)
+	}
 
 	// Check environment variable for Jira token
-	jiraToken := os.Getenv("JAI_JIRA_TOKEN")
-	if jiraToken != "" {
-		fmt.Printf("  Token: %s (from environment)\n", maskString(jiraToken))
+	token := jiraToken(activeProfile())
+	if token != "" {
+		fmt.Printf("  Token: %s (from environment)\n", maskString(token))
 	} else {
 		fmt.Println("  Token: NOT SET (set JAI_JIRA_TOKEN environment variable)")
 	}
+	if names := profileNames(); len(names) > 0 {
+		fmt.Printf("  Profiles: %s\n", strings.Join(names, ", "))
+	}
 
 	fmt.Println()
 
@@ -163,6 +179,80 @@ func setConfig(key, value string) error {
 	return nil
 }
 
+// detectConfig discovers instance-specific Jira settings and writes them to the config file
+func detectConfig() error {
+	jiraClient, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Detecting Epic Link field...")
+	fieldID, err := jiraClient.DetectEpicLinkField()
+	if err != nil {
+		return err
+	}
+	if fieldID == "" {
+		fmt.Println("No Epic Link field found on this Jira instance, leaving jira.epic_link_field unset")
+		return nil
+	}
+
+	if err := writeConfigValue("jira.epic_link_field", fieldID); err != nil {
+		return err
+	}
+	fmt.Printf("Saved jira.epic_link_field = %s to %s\n", fieldID, configFilePath())
+	return nil
+}
+
+// writeConfigValue sets a dotted key such as jira.epic_link_field in the config file,
+// preserving every other setting
+func writeConfigValue(key string, value interface{}) error {
+	configPath := configFilePath()
+
+	config := map[string]interface{}{}
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	// Walk down to the parent map, creating sections as needed
+	parts := strings.Split(key, ".")
+	section := config
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := section[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			section[part] = next
+		}
+		section = next
+	}
+	section[parts[len(parts)-1]] = value
+
+	data, err = yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	viper.Set(key, value)
+	return nil
+}
+
+// configFilePath returns the config file in use, falling back to the default location
+func configFilePath() string {
+	if used := viper.ConfigFileUsed(); used != "" {
+		return used
+	}
+	return getConfigPath()
+}
+
 // getConfigPath returns the path to the configuration file
 func getConfigPath() string {
 	home, err := os.UserHomeDir()
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/lunchboxsushi/jai/internal/ai"
+	"github.com/lunchboxsushi/jai/internal/jira"
 	"github.com/lunchboxsushi/jai/internal/types"
 	"github.com/sashabaranov/go-openai"
 	"github.com/spf13/cobra"
@@ -24,7 +25,8 @@ This command will:
 - Check OpenAI API connectivity
 - Test AI enrichment functionality
 - Verify environment variables
-- Check data directory permissions`,
+- Check data directory permissions
+- Detect the Jira Epic Link field`,
 	RunE: runDoctor,
 }
 
@@ -64,13 +66,17 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Check 6: Jira
+	fmt.Println("\n6. Checking Jira...")
+	checkJira()
+
 	fmt.Println("\n✅ All checks completed!")
 	return nil
 }
 
 func checkEnvironmentVariables() {
 	aiToken := os.Getenv("JAI_AI_TOKEN")
-	jiraToken := os.Getenv("JAI_JIRA_TOKEN")
+	token := jiraToken(activeProfile())
 
 	if aiToken == "" {
 		fmt.Println("❌ JAI_AI_TOKEN not set")
@@ -78,10 +84,10 @@ func checkEnvironmentVariables() {
 		fmt.Printf("✅ JAI_AI_TOKEN set (length: %d)\n", len(aiToken))
 	}
 
-	if jiraToken == "" {
+	if token == "" {
 		fmt.Println("⚠️  JAI_JIRA_TOKEN not set (Jira integration will be disabled)")
 	} else {
-		fmt.Printf("✅ JAI_JIRA_TOKEN set (length: %d)\n", len(jiraToken))
+		fmt.Printf("✅ JAI_JIRA_TOKEN set (length: %d)\n", len(token))
 	}
 }
 
@@ -283,3 +289,44 @@ func checkDataDirectory() error {
 
 	return nil
 }
+
+// checkJira verifies the Jira connection settings and reports the Epic Link field in use
+func checkJira() {
+	jiraClient, err := newJiraClient()
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return
+	}
+	fmt.Printf("✅ Using %s auth\n", jira.AuthMode(loadJiraConfig()))
+
+	style, err := jiraClient.ProjectStyle()
+	if err != nil {
+		fmt.Printf("⚠️  Could not read project %s, assuming %s-managed: %v\n", viper.GetString("jira.project"), style, err)
+	} else {
+		fmt.Printf("✅ Project %s is %s-managed\n", viper.GetString("jira.project"), style)
+	}
+	if style == jira.ProjectStyleTeam {
+		// Team-managed projects link epics through the parent field
+		return
+	}
+
+	detected, err := jiraClient.DetectEpicLinkField()
+	if err != nil {
+		fmt.Printf("❌ Could not query Jira fields: %v\n", err)
+		return
+	}
+
+	configured := viper.GetString("jira.epic_link_field")
+	switch {
+	case detected == "" && configured == "":
+		fmt.Println("⚠️  No Epic Link field found (team-managed projects link epics through the parent field)")
+	case detected == "":
+		fmt.Printf("⚠️  Epic Link field configured as %s, but Jira reports no Epic Link field\n", configured)
+	case configured == "":
+		fmt.Printf("✅ Epic Link field detected: %s (run 'jai config detect' to save it)\n", detected)
+	case configured != detected:
+		fmt.Printf("❌ Epic Link field configured as %s, but Jira reports %s (run 'jai config detect' to fix)\n", configured, detected)
+	default:
+		fmt.Printf("✅ Epic Link field: %s\n", configured)
+	}
+}
@@ -26,13 +26,16 @@ then enriches the content with AI, and optionally creates a Jira ticket.
 Examples:
   jai epic                    # Create new epic with template
   jai epic --no-enrich       # Skip AI enrichment
-  jai epic --no-create       # Skip Jira ticket creation`,
+  jai epic --no-create       # Skip Jira ticket creation
+  jai epic --sprint active   # Add the epic to the active sprint`,
 	RunE: runEpic,
 }
 
 func init() {
 	epicCmd.Flags().BoolVar(&noEnrich, "no-enrich", false, "Skip AI enrichment")
 	epicCmd.Flags().BoolVar(&noCreate, "no-create", false, "Skip Jira ticket creation")
+	epicCmd.Flags().StringVar(&sprintQuery, "sprint", "", "Add the new ticket to a sprint: active, next or a sprint name")
+	epicCmd.Flags().StringVar(&projectOverride, "project", "", "Create the epic in this Jira project instead of the configured one; its tasks follow it")
 	rootCmd.AddCommand(epicCmd)
 }
 
@@ -80,6 +83,7 @@ func createNewEpic(ctxManager *context.Manager, dataDir string) error {
 		Created:    time.Now(),
 		Updated:    time.Now(),
 	}
+	inheritProfile(dataDir, epic, "")
 
 	// Enrich with AI if enabled
 	if !noEnrich {
@@ -100,11 +104,18 @@ func createNewEpic(ctxManager *context.Manager, dataDir string) error {
 		}
 	}
 
+	// Check Jira will accept the epic before writing anything locally
+	if !noCreate {
+		if err := preflightCreate(epic); err != nil {
+			return err
+		}
+	}
+
 	// Generate temporary epic key for file creation
 	tempEpicKey := generateEpicKey(epic.Title)
 
 	// Initialize parser and create epic file
-	parser := markdown.NewParser(dataDir)
+	parser := newParser(dataDir)
 	epicFilePath := parser.GetEpicFilePath(tempEpicKey)
 
 	// Ensure epic file exists
@@ -142,14 +153,20 @@ func createNewEpic(ctxManager *context.Manager, dataDir string) error {
 
 	// Create Jira ticket if enabled
 	if !noCreate {
-		fmt.Println("Creating Jira epic...")
-		if err := createJiraEpic(epic); err != nil {
-			fmt.Printf("Warning: Failed to create Jira epic: %v\n", err)
+		fmt.Printf("Creating %s epic...\n", trackerName())
+		if err := createTrackerEpic(epic); err != nil {
+			fmt.Printf("Warning: Failed to create %s epic: %s\n", trackerName(), describeError(err))
+			// Keep the epic with a placeholder key until 'jai push' can create it
+			if err := queueCreate(epic, err); err != nil {
+				fmt.Printf("Warning: Failed to queue the epic for 'jai push': %v\n", err)
+			}
 		} else {
-			fmt.Printf("Jira epic created: %s\n", epic.Key)
+			fmt.Printf("%s epic created: %s\n", trackerName(), epic.Key)
+		}
 
-			// Update the epic file with the real Jira key
-			if err := updateEpicWithJiraKey(parser, epicFilePath, tempEpicKey, epic.Key); err != nil {
+		// Update the epic file with the Jira (or placeholder) key
+		if epic.Key != "" {
+			if err := updateEpicWithJiraKey(parser, epicFilePath, tempEpicKey, epic); err != nil {
 				fmt.Printf("Warning: Failed to update epic file with Jira key: %v\n", err)
 			} else {
 				// Update context with real Jira key
@@ -167,23 +184,6 @@ func createNewEpic(ctxManager *context.Manager, dataDir string) error {
 
 // openEditorForEpic opens an editor for drafting an epic
 func openEditorForEpic() (string, error) {
-	// Get editor from config or environment
-	editor := viper.GetString("general.default_editor")
-	if editor == "" {
-		editor = os.Getenv("EDITOR")
-		if editor == "" {
-			editor = "vim" // Default fallback
-		}
-	}
-
-	// Create temporary file
-	tmpFile, err := os.CreateTemp("", "jai-epic-*.md")
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %w", err)
-	}
-	defer os.Remove(tmpFile.Name())
-
-	// Write template to temp file
 	template := `## Overview
 Brief description of what this epic aims to achieve.
 
@@ -202,28 +202,8 @@ Brief description of what this epic aims to achieve.
 ## Notes
 Any additional notes or context...
 `
-	if _, err := tmpFile.WriteString(template); err != nil {
-		return "", fmt.Errorf("failed to write template: %w", err)
-	}
-	tmpFile.Close()
 
-	// Open editor
-	cmd := exec.Command(editor, tmpFile.Name())
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to run editor: %w", err)
-	}
-
-	// Read content back
-	content, err := os.ReadFile(tmpFile.Name())
-	if err != nil {
-		return "", fmt.Errorf("failed to read temp file: %w", err)
-	}
-
-	return string(content), nil
+	return openEditorWithTemplate("jai-epic-*.md", template)
 }
 
 // enrichEpic enriches an epic using AI
@@ -310,49 +290,40 @@ func addEpicToFile(parser *markdown.Parser, epicFilePath string, epic *types.Tic
 	return parser.WriteFile(epicFilePath, mdFile.Tickets)
 }
 
-// createJiraEpic creates a Jira epic
-func createJiraEpic(epic *types.Ticket) error {
-	// Get Jira config
-	jiraConfig := &types.Config{
-		Jira: struct {
-			URL           string `yaml:"url" json:"url"`
-			Username      string `yaml:"username" json:"username"`
-			Token         string `yaml:"token" json:"token"`
-			Project       string `yaml:"project" json:"project"`
-			EpicLinkField string `yaml:"epic_link_field" json:"epic_link_field"`
-		}{
-			URL:           viper.GetString("jira.url"),
-			Username:      viper.GetString("jira.username"),
-			Token:         os.Getenv("JAI_JIRA_TOKEN"),
-			Project:       viper.GetString("jira.project"),
-			EpicLinkField: viper.GetString("jira.epic_link_field"),
-		},
-	}
-
-	if jiraConfig.Jira.URL == "" || jiraConfig.Jira.Username == "" || jiraConfig.Jira.Token == "" {
-		return fmt.Errorf("Jira configuration incomplete (check URL, username, and JAI_JIRA_TOKEN environment variable)")
-	}
-
-	// Create Jira client using our internal wrapper
-	jiraClient, err := jira.NewClient(jiraConfig)
+// createTrackerEpic creates the epic in the configured tracker
+func createTrackerEpic(epic *types.Ticket) error {
+	// Create the backend for the epic's profile and project
+	backend, err := newTicketBackend(epic)
 	if err != nil {
-		return fmt.Errorf("failed to create Jira client: %w", err)
+		return err
 	}
 
 	// Create the epic using our wrapper
-	createdEpic, err := jiraClient.CreateTicket(epic)
+	createdEpic, err := backend.CreateTicket(epic)
 	if err != nil {
-		return fmt.Errorf("failed to create Jira epic: %w", err)
+		return fmt.Errorf("failed to create %s epic: %w", trackerName(), err)
 	}
 
 	// Update the epic with the created data
 	*epic = *createdEpic
 
+	// Attachments and sprints only exist in Jira
+	if jiraClient, ok := backend.(*jira.Client); ok {
+		// Upload files linked from the description now that there is an issue to attach them to
+		attachAfterCreate(jiraClient, epic)
+
+		if sprintQuery != "" {
+			addToSprint(jiraClient, epic, sprintQuery)
+		}
+	}
+
 	return nil
 }
 
 // updateEpicWithJiraKey updates the epic file with the real Jira key
-func updateEpicWithJiraKey(parser *markdown.Parser, epicFilePath string, tempKey string, realKey string) error {
+func updateEpicWithJiraKey(parser *markdown.Parser, epicFilePath string, tempKey string, epic *types.Ticket) error {
+	realKey := epic.Key
+
 	// Parse existing file
 	mdFile, err := parser.ParseFile(epicFilePath)
 	if err != nil {
@@ -363,6 +334,8 @@ func updateEpicWithJiraKey(parser *markdown.Parser, epicFilePath string, tempKey
 	for i, ticket := range mdFile.Tickets {
 		if ticket.Key == tempKey || (ticket.Key == "" && ticket.Title != "") {
 			mdFile.Tickets[i].Key = realKey
+			mdFile.Tickets[i].Attachments = epic.Attachments
+			mdFile.Tickets[i].Sprint = epic.Sprint
 			break
 		}
 	}
@@ -396,24 +369,7 @@ func renameEpicFile(currentPath string, epicKey string, tickets []types.Ticket)
 		return "", fmt.Errorf("could not find epic title for key: %s", epicKey)
 	}
 
-	// Create the new filename in the correct format
-	// Convert title to filename-safe format
-	safeTitle := strings.ReplaceAll(epicTitle, " ", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "/", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "\\", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, ":", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "*", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "?", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "\"", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "<", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, ">", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "|", "-")
-
-	// Remove any double dashes and trim
-	safeTitle = strings.ReplaceAll(safeTitle, "--", "-")
-	safeTitle = strings.Trim(safeTitle, "-")
-
-	newFilename := fmt.Sprintf("%s-%s.md", epicKey, safeTitle)
+	newFilename := ticketFileName(epicKey, epicTitle)
 
 	// Get the directory of the current file
 	dir := filepath.Dir(currentPath)
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lunchboxsushi/jai/internal/jira"
+	"github.com/spf13/viper"
+)
+
+// jiraErrorHint suggests how to fix a failed Jira request, or returns "" if there's nothing to add
+func jiraErrorHint(err error) string {
+	var (
+		authErr       *jira.AuthError
+		permissionErr *jira.PermissionError
+		notFoundErr   *jira.NotFoundError
+		validationErr *jira.ValidationError
+		rateLimitErr  *jira.RateLimitError
+	)
+
+	switch {
+	case errors.As(err, &authErr):
+		if jira.AuthMode(loadJiraConfig()) == jira.AuthBasic {
+			return "Check jira.username and JAI_JIRA_TOKEN; Jira Cloud needs an API token, and Server / Data Center needs 'jira.auth: bearer' with a personal access token"
+		}
+		return "Check that your token is valid and has not expired"
+	case errors.As(err, &permissionErr):
+		return fmt.Sprintf("Your Jira account lacks permission for this; ask a project admin for access to %s", viper.GetString("jira.project"))
+	case errors.As(err, &notFoundErr):
+		return "Check the ticket key and project, and that your account can see them"
+	case errors.As(err, &validationErr):
+		if len(validationErr.Fields) > 0 {
+			return "Fix the fields above in the ticket's markdown and try again"
+		}
+		return ""
+	case errors.As(err, &rateLimitErr):
+		return "Jira is rate limiting requests; wait a minute and try again"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "Jira did not respond in time; check your connection or raise jira.timeout"
+	}
+	return ""
+}
+
+// describeError formats an error for display, adding a hint for Jira errors
+func describeError(err error) string {
+	if hint := jiraErrorHint(err); hint != "" {
+		return fmt.Sprintf("%v\n  → %s", err, hint)
+	}
+	return err.Error()
+}
@@ -20,12 +20,17 @@ var focusCmd = &cobra.Command{
 	Short: "Set current context by fuzzy-matching epic/task title",
 	Long: `Set current context by fuzzy-matching epic or task titles.
 
+With --profile (or JAI_PROFILE) set, only tickets created under that profile are
+offered. Otherwise tickets from every profile are, tagged with the profile they
+belong to (e.g. "@work").
+
 Examples:
   jai focus "observability"     # Focus on epic/task containing "observability"
   jai focus "SRE-1234"          # Focus on specific ticket by key
   jai focus                     # Show interactive epic selection
   jai focus --task              # Start at task level (skip epics)
-  jai focus --subtask           # Start at subtask level (skip epics/tasks)`,
+  jai focus --subtask           # Start at subtask level (skip epics/tasks)
+  jai focus --profile work      # Pick from the work profile's epics only`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runFocus,
 }
@@ -82,7 +87,7 @@ func runFocus(cmd *cobra.Command, args []string) error {
 
 // interactiveFocus provides a hierarchical selection: epics -> tasks -> subtasks
 func interactiveFocus(ctxManager *context.Manager, dataDir string) error {
-	parser := markdown.NewParser(dataDir)
+	parser := newParser(dataDir)
 	ticketsDir := filepath.Join(dataDir, "tickets")
 
 	// 1. List all epics
@@ -96,7 +101,7 @@ func interactiveFocus(ctxManager *context.Manager, dataDir string) error {
 
 	fmt.Println("Select an epic:")
 	for i, epic := range epics {
-		fmt.Printf("%d. %s [%s]\n", i+1, parser.RemoveJiraKey(epic.Title), epic.Key)
+		fmt.Printf("%d. %s [%s]%s\n", i+1, parser.RemoveJiraKey(epic.Title), epic.Key, profileTag(epic))
 	}
 	fmt.Print("Enter number (or blank to cancel): ")
 	selectedEpicIdx := readNumber(len(epics))
@@ -108,7 +113,7 @@ func interactiveFocus(ctxManager *context.Manager, dataDir string) error {
 	if err := ctxManager.SetEpic(epic.Key, epic.ID); err != nil {
 		return fmt.Errorf("failed to set epic context: %w", err)
 	}
-	fmt.Printf("Focused on epic: %s [%s]\n", parser.RemoveJiraKey(epic.Title), epic.Key)
+	fmt.Printf("Focused on epic: %s [%s]%s\n", parser.RemoveJiraKey(epic.Title), epic.Key, profileTag(epic))
 
 	// 2. List tasks and subtasks under the selected epic
 	tasks, err := listTasksForEpic(parser, ticketsDir, epic.Key)
@@ -137,7 +142,7 @@ func interactiveFocus(ctxManager *context.Manager, dataDir string) error {
 		if ticket.Type == types.TicketTypeSubtask {
 			typeLabel = "Subtask"
 		}
-		fmt.Printf("%d. %s [%s] (%s)\n", i+1, parser.RemoveJiraKey(ticket.Title), ticket.Key, typeLabel)
+		fmt.Printf("%d. %s [%s] (%s)%s\n", i+1, parser.RemoveJiraKey(ticket.Title), ticket.Key, typeLabel, profileTag(ticket))
 	}
 	fmt.Print("Enter number (or blank to stay on epic): ")
 	selectedIdx := readNumber(len(combined))
@@ -153,7 +158,7 @@ func interactiveFocus(ctxManager *context.Manager, dataDir string) error {
 		if err := ctxManager.SetTask(ticket.Key, ticket.ID); err != nil {
 			return fmt.Errorf("failed to set task context: %w", err)
 		}
-		fmt.Printf("Focused on task: %s [%s]\n", parser.RemoveJiraKey(ticket.Title), ticket.Key)
+		fmt.Printf("Focused on task: %s [%s]%s\n", parser.RemoveJiraKey(ticket.Title), ticket.Key, profileTag(ticket))
 
 		// Optionally show subtasks under this task
 		subtasks, err := listSubtasksForTask(parser, ticketsDir, ticket.Key)
@@ -191,7 +196,7 @@ func interactiveFocus(ctxManager *context.Manager, dataDir string) error {
 				return fmt.Errorf("failed to set subtask context: %w", err)
 			}
 		}
-		fmt.Printf("Focused on subtask: %s [%s]\n", parser.RemoveJiraKey(ticket.Title), ticket.Key)
+		fmt.Printf("Focused on subtask: %s [%s]%s\n", parser.RemoveJiraKey(ticket.Title), ticket.Key, profileTag(ticket))
 	}
 	return nil
 }
@@ -216,7 +221,7 @@ func listEpics(parser *markdown.Parser, ticketsDir string) ([]types.Ticket, erro
 			continue
 		}
 		for _, ticket := range mdFile.Tickets {
-			if ticket.Type == types.TicketTypeEpic {
+			if ticket.Type == types.TicketTypeEpic && inProfile(ticket) {
 				epics = append(epics, ticket)
 			}
 		}
@@ -358,7 +363,7 @@ func focusByKey(ctxManager *context.Manager, key string) error {
 
 // focusByFuzzyMatch focuses on a ticket by fuzzy matching the title
 func focusByFuzzyMatch(ctxManager *context.Manager, dataDir string, query string) error {
-	parser := markdown.NewParser(dataDir)
+	parser := newParser(dataDir)
 	ticketsDir := filepath.Join(dataDir, "tickets")
 
 	// Search for matching tickets
@@ -380,7 +385,7 @@ func focusByFuzzyMatch(ctxManager *context.Manager, dataDir string, query string
 	// Multiple matches, show selection
 	fmt.Printf("Multiple matches found for '%s':\n", query)
 	for i, ticket := range matches {
-		fmt.Printf("%d. %s [%s] (%s)\n", i+1, parser.RemoveJiraKey(ticket.Title), ticket.Key, ticket.Type)
+		fmt.Printf("%d. %s [%s] (%s)%s\n", i+1, parser.RemoveJiraKey(ticket.Title), ticket.Key, ticket.Type, profileTag(ticket))
 	}
 
 	// For now, just use the first match
@@ -419,7 +424,7 @@ func searchTickets(parser *markdown.Parser, ticketsDir string, query string) ([]
 		// Look for matching tickets in this file
 		for _, ticket := range mdFile.Tickets {
 			titleLower := strings.ToLower(ticket.Title)
-			if strings.Contains(titleLower, queryLower) {
+			if strings.Contains(titleLower, queryLower) && inProfile(ticket) {
 				matches = append(matches, ticket)
 			}
 		}
@@ -435,7 +440,7 @@ func setTicketContext(ctxManager *context.Manager, parser *markdown.Parser, tick
 		if err := ctxManager.SetEpic(ticket.Key, ticket.ID); err != nil {
 			return fmt.Errorf("failed to set epic context: %w", err)
 		}
-		fmt.Printf("Focused on epic: %s [%s]\n", parser.RemoveJiraKey(ticket.Title), ticket.Key)
+		fmt.Printf("Focused on epic: %s [%s]%s\n", parser.RemoveJiraKey(ticket.Title), ticket.Key, profileTag(ticket))
 	case types.TicketTypeTask:
 		// For tasks, set both epic and task context if epic is available
 		if ticket.EpicKey != "" {
@@ -451,7 +456,7 @@ func setTicketContext(ctxManager *context.Manager, parser *markdown.Parser, tick
 				return fmt.Errorf("failed to set task context: %w", err)
 			}
 		}
-		fmt.Printf("Focused on task: %s [%s]\n", parser.RemoveJiraKey(ticket.Title), ticket.Key)
+		fmt.Printf("Focused on task: %s [%s]%s\n", parser.RemoveJiraKey(ticket.Title), ticket.Key, profileTag(ticket))
 	case types.TicketTypeSubtask:
 		// For subtasks, set epic, task, and subtask context
 		if ticket.EpicKey != "" && ticket.ParentKey != "" {
@@ -480,7 +485,7 @@ func setTicketContext(ctxManager *context.Manager, parser *markdown.Parser, tick
 				return fmt.Errorf("failed to set subtask context: %w", err)
 			}
 		}
-		fmt.Printf("Focused on subtask: %s [%s]\n", parser.RemoveJiraKey(ticket.Title), ticket.Key)
+		fmt.Printf("Focused on subtask: %s [%s]%s\n", parser.RemoveJiraKey(ticket.Title), ticket.Key, profileTag(ticket))
 	}
 
 	return nil
@@ -495,7 +500,7 @@ func isJiraKey(s string) bool {
 
 // interactiveFocusTasks provides direct task selection (including orphan tasks)
 func interactiveFocusTasks(ctxManager *context.Manager, dataDir string) error {
-	parser := markdown.NewParser(dataDir)
+	parser := newParser(dataDir)
 	ticketsDir := filepath.Join(dataDir, "tickets")
 
 	// List all tasks (including orphan tasks)
@@ -515,7 +520,7 @@ func interactiveFocusTasks(ctxManager *context.Manager, dataDir string) error {
 		} else {
 			epicInfo = " (Orphan)"
 		}
-		fmt.Printf("%d. %s [%s]%s\n", i+1, parser.RemoveJiraKey(task.Title), task.Key, epicInfo)
+		fmt.Printf("%d. %s [%s]%s%s\n", i+1, parser.RemoveJiraKey(task.Title), task.Key, epicInfo, profileTag(task))
 	}
 	fmt.Print("Enter number (or blank to cancel): ")
 	selectedIdx := readNumber(len(tasks))
@@ -530,7 +535,7 @@ func interactiveFocusTasks(ctxManager *context.Manager, dataDir string) error {
 
 // interactiveFocusSubtasks provides direct subtask selection
 func interactiveFocusSubtasks(ctxManager *context.Manager, dataDir string) error {
-	parser := markdown.NewParser(dataDir)
+	parser := newParser(dataDir)
 	ticketsDir := filepath.Join(dataDir, "tickets")
 
 	// List all subtasks
@@ -555,7 +560,7 @@ func interactiveFocusSubtasks(ctxManager *context.Manager, dataDir string) error
 				parentInfo = fmt.Sprintf(" (Epic: %s)", subtask.EpicKey)
 			}
 		}
-		fmt.Printf("%d. %s [%s]%s\n", i+1, parser.RemoveJiraKey(subtask.Title), subtask.Key, parentInfo)
+		fmt.Printf("%d. %s [%s]%s%s\n", i+1, parser.RemoveJiraKey(subtask.Title), subtask.Key, parentInfo, profileTag(subtask))
 	}
 	fmt.Print("Enter number (or blank to cancel): ")
 	selectedIdx := readNumber(len(subtasks))
@@ -588,7 +593,7 @@ func listAllTasks(parser *markdown.Parser, ticketsDir string) ([]types.Ticket, e
 			continue
 		}
 		for _, ticket := range mdFile.Tickets {
-			if ticket.Type == types.TicketTypeTask {
+			if ticket.Type == types.TicketTypeTask && inProfile(ticket) {
 				tasks = append(tasks, ticket)
 			}
 		}
@@ -616,7 +621,7 @@ func listAllSubtasks(parser *markdown.Parser, ticketsDir string) ([]types.Ticket
 			continue
 		}
 		for _, ticket := range mdFile.Tickets {
-			if ticket.Type == types.TicketTypeSubtask {
+			if ticket.Type == types.TicketTypeSubtask && inProfile(ticket) {
 				subtasks = append(subtasks, ticket)
 			}
 		}
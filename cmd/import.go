@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lunchboxsushi/jai/internal/jira"
+	"github.com/lunchboxsushi/jai/internal/markdown"
+	"github.com/lunchboxsushi/jai/internal/snapshot"
+	"github.com/lunchboxsushi/jai/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <EPIC-KEY>",
+	Short: "Import an existing Jira epic with its tasks and subtasks",
+	Long: `Import an epic that was created outside of jai, together with all of its tasks
+and subtasks. Each ticket is written to its own markdown file under the tickets
+directory, in the same layout produced by 'jai epic', 'jai task' and 'jai subtask',
+so it shows up in 'jai list', 'jai status' and 'jai focus' straight away.
+
+Tickets that already exist locally are not overwritten; only their status,
+priority, parent metadata and comments are refreshed.
+
+Examples:
+  jai import SRE-1234         # Import epic SRE-1234 and everything under it`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+}
+
+// importStats counts what happened to each ticket during an import
+type importStats struct {
+	created   int
+	updated   int
+	unchanged int
+}
+
+// localTicket is a ticket already present in the tickets directory
+type localTicket struct {
+	path   string
+	ticket types.Ticket
+}
+
+// ticketImporter writes remote tickets into the local tickets directory
+type ticketImporter struct {
+	parser     *markdown.Parser
+	snapshots  *snapshot.Store
+	ticketsDir string
+	existing   map[string]localTicket
+	stats      importStats
+
+	// Profile and default project the tickets are imported from
+	profile string
+	project string
+}
+
+// newTicketImporter indexes the existing local tickets so imports don't create duplicates
+func newTicketImporter(dataDir string, parser *markdown.Parser) (*ticketImporter, error) {
+	ticketsDir := filepath.Join(dataDir, "tickets")
+	if err := os.MkdirAll(ticketsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create tickets directory: %w", err)
+	}
+
+	mdFiles, err := findTicketFiles(dataDir, parser)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]localTicket)
+	for _, mdFile := range mdFiles {
+		for _, ticket := range mdFile.Tickets {
+			if ticket.Key != "" {
+				existing[ticket.Key] = localTicket{path: mdFile.Path, ticket: ticket}
+			}
+		}
+	}
+
+	return &ticketImporter{
+		parser:     parser,
+		snapshots:  snapshot.NewStore(dataDir),
+		ticketsDir: ticketsDir,
+		existing:   existing,
+		profile:    activeProfile(),
+		project:    loadJiraConfig().Jira.Project,
+	}, nil
+}
+
+// importTicket writes a new ticket file or refreshes the metadata of an existing one
+func (ti *ticketImporter) importTicket(remote *types.Ticket) error {
+	if local, ok := ti.existing[remote.Key]; ok {
+		return ti.refreshTicket(local, remote)
+	}
+
+	ticket := *remote
+	ticket.RawContent = strings.TrimSpace(remote.Description)
+
+	// Record where the ticket came from, so follow-up commands and new children use the same
+	// profile, and the same project if it isn't the profile's default
+	ticket.Profile = ti.profile
+	if project, _, ok := strings.Cut(ticket.Key, "-"); ok && !strings.EqualFold(project, ti.project) {
+		ticket.Project = project
+	}
+
+	filePath := filepath.Join(ti.ticketsDir, ticketFileName(ticket.Key, ticket.Title))
+	var err error
+	switch ticket.Type {
+	case types.TicketTypeEpic:
+		err = ti.parser.WriteFile(filePath, []types.Ticket{ticket})
+	case types.TicketTypeSubtask:
+		err = os.WriteFile(filePath, []byte(generateSubtaskMarkdown(ti.parser, &ticket)), 0644)
+	default:
+		err = os.WriteFile(filePath, []byte(generateTaskMarkdown(ti.parser, &ticket)), 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", filePath, err)
+	}
+
+	// The imported description is the base for three-way merges on the next sync
+	if err := ti.snapshots.Put(ticket.Key, ticket.RawContent); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	ti.existing[ticket.Key] = localTicket{path: filePath, ticket: ticket}
+	ti.stats.created++
+	fmt.Printf("+ %s: %s\n", ticket.Key, ticket.Title)
+	return nil
+}
+
+// refreshTicket updates the Jira-owned metadata, links and comments of a ticket that already exists locally
+func (ti *ticketImporter) refreshTicket(local localTicket, remote *types.Ticket) error {
+	ticket := local.ticket
+	changed := false
+	for _, field := range []struct {
+		local  *string
+		remote string
+	}{
+		{&ticket.Status, remote.Status},
+		{&ticket.Priority, remote.Priority},
+		{&ticket.EpicKey, remote.EpicKey},
+		{&ticket.ParentKey, remote.ParentKey},
+		{&ticket.Sprint, remote.Sprint},
+	} {
+		if field.remote != "" && *field.local != field.remote {
+			*field.local = field.remote
+			changed = true
+		}
+	}
+
+	if !commentsEqual(ticket.Comments, remote.Comments) {
+		ticket.Comments = remote.Comments
+		changed = true
+	}
+
+	if customFields, _, pulled := mergeCustomFields(loadJiraConfig(), ticket.CustomFields, remote.CustomFields, false); pulled {
+		ticket.CustomFields = customFields
+		changed = true
+	}
+
+	if !linksEqual(ticket.Links, remote.Links) {
+		ticket.Links = remote.Links
+		changed = true
+	}
+
+	if !changed {
+		ti.stats.unchanged++
+		if verbose {
+			fmt.Printf("= %s: up to date\n", ticket.Key)
+		}
+		return nil
+	}
+
+	if err := ti.parser.UpdateTicket(local.path, ticket); err != nil {
+		return fmt.Errorf("failed to update %s: %w", local.path, err)
+	}
+
+	ti.existing[ticket.Key] = localTicket{path: local.path, ticket: ticket}
+	ti.stats.updated++
+	fmt.Printf("↻ %s: %s\n", ticket.Key, ticket.Title)
+	return nil
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	if err := requireJira("import"); err != nil {
+		return err
+	}
+
+	epicKey := strings.ToUpper(strings.TrimSpace(args[0]))
+
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
+	}
+
+	jiraClient, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	epic, err := jiraClient.GetTicket(epicKey)
+	if err != nil {
+		return fmt.Errorf("failed to fetch epic %s: %w", epicKey, err)
+	}
+	if epic.Type != types.TicketTypeEpic {
+		return fmt.Errorf("%s is not an epic (use the epic key to import a whole hierarchy)", epicKey)
+	}
+
+	tasks, err := fetchEpicChildren(jiraClient, epicKey)
+	if err != nil {
+		return err
+	}
+
+	subtasks, err := fetchSubtasks(jiraClient, tasks)
+	if err != nil {
+		return err
+	}
+
+	// Everything under the epic belongs to it, even if Jira only links subtasks to their task
+	for _, ticket := range append(tasks, subtasks...) {
+		ticket.EpicKey = epicKey
+	}
+
+	parser := newParser(dataDir)
+	importer, err := newTicketImporter(dataDir, parser)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, ticket := range append(append([]*types.Ticket{epic}, tasks...), subtasks...) {
+		if err := importer.importTicket(ticket); err != nil {
+			fmt.Printf("✗ %s: %v\n", ticket.Key, err)
+			failed++
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Import complete: %d created, %d updated, %d unchanged, %d failed\n",
+		importer.stats.created, importer.stats.updated, importer.stats.unchanged, failed)
+	fmt.Printf("Run 'jai focus %s' to start working on it\n", epicKey)
+	return nil
+}
+
+// fetchEpicChildren returns the tasks linked to an epic through either the epic link or parent field
+func fetchEpicChildren(jiraClient *jira.Client, epicKey string) ([]*types.Ticket, error) {
+	tasks, err := jiraClient.SearchAll(fmt.Sprintf(`parent = %s OR "Epic Link" = %s ORDER BY key`, epicKey, epicKey), jira.SearchOptions{})
+	if err != nil {
+		// Instances without the Epic Link field reject the whole query, so retry with parent only
+		tasks, err = jiraClient.SearchAll(fmt.Sprintf("parent = %s ORDER BY key", epicKey), jira.SearchOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tasks for epic %s: %w", epicKey, err)
+		}
+	}
+
+	var children []*types.Ticket
+	for _, task := range tasks {
+		if task.Type == types.TicketTypeEpic {
+			continue
+		}
+		children = append(children, task)
+	}
+	return children, nil
+}
+
+// fetchSubtasks returns the subtasks of the given tasks
+func fetchSubtasks(jiraClient *jira.Client, tasks []*types.Ticket) ([]*types.Ticket, error) {
+	var keys []string
+	for _, task := range tasks {
+		if task.Type == types.TicketTypeTask {
+			keys = append(keys, task.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	subtasks, err := jiraClient.SearchAll(fmt.Sprintf("parent in (%s) ORDER BY key", strings.Join(keys, ", ")), jira.SearchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch subtasks: %w", err)
+	}
+	return subtasks, nil
+}
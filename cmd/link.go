@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lunchboxsushi/jai/internal/jira"
+	"github.com/lunchboxsushi/jai/internal/markdown"
+	"github.com/lunchboxsushi/jai/internal/tracker"
+	"github.com/lunchboxsushi/jai/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var linkCmd = &cobra.Command{
+	Use:   "link <key> <relation> <key>",
+	Short: "Link two tickets, e.g. one blocking another",
+	Long: `Create an issue link in Jira between two tickets and record it in the Links
+section of both tickets' markdown files.
+
+The relation is matched against the link types configured on your Jira instance,
+by their outward description ("blocks"), inward description ("is blocked by") or
+name ("Blocks"). Use --types to list what is available.
+
+Tickets blocked by an unfinished ticket are marked with ⛔ in 'jai list' and
+'jai status'.
+
+Examples:
+  jai link SRE-1 blocks SRE-2          # SRE-2 can't start until SRE-1 is done
+  jai link SRE-2 is blocked by SRE-1   # The same link, read from SRE-2
+  jai link SRE-1 relates to SRE-3
+  jai link --types                     # List the available link types`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if linkListTypes {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(3)(cmd, args)
+	},
+	RunE: runLink,
+}
+
+var linkListTypes bool
+
+// doneStatuses are the statuses after which a ticket no longer blocks anything
+var doneStatuses = []string{"done", "closed", "resolved", "cancelled", "canceled"}
+
+func init() {
+	linkCmd.Flags().BoolVar(&linkListTypes, "types", false, "List the link types available in Jira")
+	rootCmd.AddCommand(linkCmd)
+}
+
+func runLink(cmd *cobra.Command, args []string) error {
+	if err := requireJira("link"); err != nil {
+		return err
+	}
+
+	// Both tickets have to be on the same Jira instance, so the first one picks the profile
+	jiraClient, err := newJiraClient()
+	if len(args) > 0 {
+		jiraClient, err = newKeyClient(strings.ToUpper(strings.TrimSpace(args[0])))
+	}
+	if err != nil {
+		return err
+	}
+
+	linkTypes, err := jiraClient.GetLinkTypes()
+	if err != nil {
+		return err
+	}
+
+	if linkListTypes {
+		for _, t := range linkTypes {
+			fmt.Printf("%-20s %s / %s\n", t.Name, t.Outward, t.Inward)
+		}
+		return nil
+	}
+
+	fromKey := strings.ToUpper(strings.TrimSpace(args[0]))
+	toKey := strings.ToUpper(strings.TrimSpace(args[len(args)-1]))
+	relation := strings.Join(args[1:len(args)-1], " ")
+
+	linkType, inward, err := matchLinkType(linkTypes, relation)
+	if err != nil {
+		return err
+	}
+
+	// An inward relation reads the link backwards: "A is blocked by B" is "B blocks A"
+	sourceKey, targetKey := fromKey, toKey
+	if inward {
+		sourceKey, targetKey = toKey, fromKey
+	}
+
+	if err := jiraClient.LinkTickets(linkType.Name, sourceKey, targetKey); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s %s\n", sourceKey, linkType.Outward, targetKey)
+
+	// Record the link on both sides in the local markdown files
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
+	}
+	parser := newParser(dataDir)
+	addLocalLink(dataDir, parser, sourceKey, types.Link{Relation: linkType.Outward, Key: targetKey})
+	addLocalLink(dataDir, parser, targetKey, types.Link{Relation: linkType.Inward, Key: sourceKey})
+
+	return nil
+}
+
+// matchLinkType finds the link type a relation refers to, and whether it was given
+// by its inward description
+func matchLinkType(linkTypes []jira.LinkType, relation string) (*jira.LinkType, bool, error) {
+	normalized := tracker.NormalizeStatus(relation)
+	for i, t := range linkTypes {
+		if tracker.NormalizeStatus(t.Outward) == normalized {
+			return &linkTypes[i], false, nil
+		}
+	}
+	for i, t := range linkTypes {
+		if tracker.NormalizeStatus(t.Inward) == normalized {
+			return &linkTypes[i], true, nil
+		}
+	}
+	for i, t := range linkTypes {
+		if tracker.NormalizeStatus(t.Name) == normalized {
+			return &linkTypes[i], false, nil
+		}
+	}
+
+	var available []string
+	for _, t := range linkTypes {
+		available = append(available, fmt.Sprintf("%q", t.Outward))
+		if t.Inward != t.Outward {
+			available = append(available, fmt.Sprintf("%q", t.Inward))
+		}
+	}
+	return nil, false, fmt.Errorf("no link type matches %q, available: %s", relation, strings.Join(available, ", "))
+}
+
+// addLocalLink adds a link to a ticket's markdown file, if the ticket is tracked locally
+func addLocalLink(dataDir string, parser *markdown.Parser, key string, link types.Link) {
+	filePath, ticket, err := findTicketByKey(dataDir, parser, key)
+	if err != nil {
+		return
+	}
+
+	for _, existing := range ticket.Links {
+		if existing.Key == link.Key && existing.Relation == link.Relation {
+			return
+		}
+	}
+
+	// Use the local status of the linked ticket until the next sync fills it in
+	if _, other, err := findTicketByKey(dataDir, parser, link.Key); err == nil {
+		link.Status = other.Status
+	}
+
+	ticket.Links = append(ticket.Links, link)
+	if err := parser.UpdateTicket(filePath, *ticket); err != nil {
+		fmt.Printf("Warning: Failed to add link to %s: %v\n", filePath, err)
+	}
+}
+
+// isBlocked reports whether a ticket is blocked by a ticket that isn't done yet
+func isBlocked(ticket types.Ticket) bool {
+	for _, link := range ticket.Links {
+		if tracker.NormalizeStatus(link.Relation) != "isblockedby" {
+			continue
+		}
+		done := false
+		for _, status := range doneStatuses {
+			if tracker.NormalizeStatus(link.Status) == status {
+				done = true
+				break
+			}
+		}
+		if !done {
+			return true
+		}
+	}
+	return false
+}
+
+// blockedMarker returns the prefix shown before blocked tickets in list and status
+func blockedMarker(ticket types.Ticket) string {
+	if isBlocked(ticket) {
+		return "⛔ "
+	}
+	return ""
+}
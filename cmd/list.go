@@ -9,7 +9,6 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	treepkg "github.com/charmbracelet/lipgloss/tree"
 	"github.com/lunchboxsushi/jai/internal/context"
-	"github.com/lunchboxsushi/jai/internal/markdown"
 	"github.com/lunchboxsushi/jai/internal/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -21,12 +20,17 @@ var listCmd = &cobra.Command{
 	Long: `List all tickets in a hierarchical tree structure. Shows epics with their tasks and subtasks.
 Orphan tasks (tasks without epics) are shown at the end.
 
+With --profile (or JAI_PROFILE) set, only tickets created under that profile are
+listed. Otherwise every ticket is, and ones from a profile are tagged with it
+(e.g. "@work").
+
 Examples:
   jai list              # Show all tickets in tree structure
   jai list epic         # Show only epics
   jai list task         # Show only tasks
   jai list subtask      # Show only subtasks
-  jai list orphan       # Show only orphan tasks`,
+  jai list orphan       # Show only orphan tasks
+  jai list --profile work  # Show only tickets in the work profile`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runList,
 }
@@ -52,7 +56,7 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load context: %w", err)
 	}
 
-	parser := markdown.NewParser(dataDir)
+	parser := newParser(dataDir)
 
 	// Get all tickets
 	allTickets, err := findAllTickets(dataDir, parser)
@@ -60,6 +64,9 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to find tickets: %w", err)
 	}
 
+	// Only show the active profile's tickets when one is picked
+	allTickets = filterProfile(allTickets)
+
 	if len(allTickets) == 0 {
 		fmt.Println("No tickets found.")
 		return nil
@@ -106,7 +113,11 @@ func listAllInTree(allTickets []types.Ticket, ctx *types.Context) error {
 	}
 
 	// Build tree structure
-	tree := treepkg.New().Root("📋 All Tickets")
+	root := "📋 All Tickets"
+	if profile := activeProfile(); profile != "" {
+		root = fmt.Sprintf("📋 All Tickets (%s)", profile)
+	}
+	tree := treepkg.New().Root(root)
 	tree.Enumerator(treepkg.RoundedEnumerator)
 
 	// Add epics with their tasks and subtasks
@@ -195,13 +206,13 @@ func formatTicketTitle(ticketType string, ticket types.Ticket, isFocused bool) s
 		desc = lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Bold(true).Render(title)
 	}
 
-	label := fmt.Sprintf("%s %s: %s", prefix, keyPart, desc)
+	label := fmt.Sprintf("%s %s: %s%s", prefix, keyPart, desc, profileTag(ticket))
 
 	if isFocused {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("#ffb300")).Bold(true).Render("*") + label
+		return blockedMarker(ticket) + lipgloss.NewStyle().Foreground(lipgloss.Color("#ffb300")).Bold(true).Render("*") + label
 	}
 
-	return lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Faint(true).Render(label)
+	return blockedMarker(ticket) + lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Faint(true).Render(label)
 }
 
 // listEpicsOnly shows only epics
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jai/internal/context"
+	"github.com/lunchboxsushi/jai/internal/types"
+	"github.com/lunchboxsushi/jai/internal/worklog"
+	"github.com/spf13/cobra"
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log [duration] [key]",
+	Short: "Log time against the focused ticket",
+	Long: `Log time spent on the focused subtask or task (or the given key). Every entry is
+recorded in a local ledger first and then posted to Jira as a worklog, so time can
+still be logged while offline. Entries that could not be posted are retried with
+--flush.
+
+Durations use Go syntax, e.g. 45m, 1h or 1h30m.
+
+Examples:
+  jai log 1h30m                        # Log time on the focused ticket
+  jai log 45m -m "Pairing on rollout"  # Log time with a note
+  jai log 2h SRE-1234 --date 2024-05-01
+  jai log --flush                      # Post entries that failed to sync
+  jai log --report                     # Show today's time grouped by epic
+  jai log --report --week              # Show this week's time grouped by epic`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runLog,
+}
+
+var (
+	logMessage string
+	logDate    string
+	logFlush   bool
+	logReport  bool
+	logWeek    bool
+)
+
+func init() {
+	logCmd.Flags().StringVarP(&logMessage, "message", "m", "", "Note to attach to the worklog")
+	logCmd.Flags().StringVar(&logDate, "date", "", "Day the work was done (YYYY-MM-DD, default today)")
+	logCmd.Flags().BoolVar(&logFlush, "flush", false, "Post entries that have not reached Jira yet")
+	logCmd.Flags().BoolVar(&logReport, "report", false, "Show logged time grouped by epic")
+	logCmd.Flags().BoolVar(&logWeek, "week", false, "With --report, cover the current week instead of today")
+	rootCmd.AddCommand(logCmd)
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
+	}
+
+	ledger := worklog.NewLedger(dataDir)
+	if err := ledger.Load(); err != nil {
+		return err
+	}
+
+	switch {
+	case logReport:
+		return printWorklogReport(ledger, time.Now(), logWeek)
+	case logFlush:
+		if err := requireJira("log --flush"); err != nil {
+			return err
+		}
+		return flushWorklog(ledger)
+	case len(args) == 0:
+		return fmt.Errorf("a duration is required, e.g. 'jai log 1h30m'")
+	}
+
+	duration, err := time.ParseDuration(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid duration %q (use e.g. 45m, 1h or 1h30m): %w", args[0], err)
+	}
+	// Jira tracks time in whole minutes
+	duration = duration.Round(time.Minute)
+	if duration < time.Minute {
+		return fmt.Errorf("duration must be at least one minute")
+	}
+
+	started, err := worklogStartTime(logDate, time.Now())
+	if err != nil {
+		return err
+	}
+
+	key, err := resolveTicketKey(dataDir, args[1:])
+	if err != nil {
+		return err
+	}
+
+	entry := ledger.Add(key, findWorklogEpic(dataDir, key), started, duration, logMessage)
+	if err := ledger.Save(); err != nil {
+		return err
+	}
+
+	// Only Jira keeps worklogs; with other trackers the ledger is the record
+	if !usesJira() {
+		fmt.Printf("Logged %s on %s\n", formatWorklogDuration(duration), key)
+		return nil
+	}
+
+	if err := postWorklogEntry(entry); err != nil {
+		fmt.Printf("Logged %s on %s locally, but posting to Jira failed: %s\n", formatWorklogDuration(duration), key, describeError(err))
+		fmt.Println("Run 'jai log --flush' to retry")
+	} else {
+		fmt.Printf("Logged %s on %s\n", formatWorklogDuration(duration), key)
+	}
+
+	return ledger.Save()
+}
+
+// worklogStartTime returns when the logged work started, honouring --date
+func worklogStartTime(date string, now time.Time) (time.Time, error) {
+	if date == "" {
+		return now, nil
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", date, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD): %w", date, err)
+	}
+
+	// Keep the current time of day so entries on the same date stay ordered
+	return time.Date(day.Year(), day.Month(), day.Day(), now.Hour(), now.Minute(), 0, 0, now.Location()), nil
+}
+
+// findWorklogEpic returns the epic a ticket rolls up to, for grouping reports
+func findWorklogEpic(dataDir, key string) string {
+	parser := newParser(dataDir)
+	allTickets, err := findAllTickets(dataDir, parser)
+	if err == nil {
+		byKey := make(map[string]types.Ticket)
+		for _, ticket := range allTickets {
+			if ticket.Key != "" {
+				byKey[ticket.Key] = ticket
+			}
+		}
+
+		if ticket, ok := byKey[key]; ok {
+			switch {
+			case ticket.Type == types.TicketTypeEpic:
+				return ticket.Key
+			case ticket.EpicKey != "":
+				return ticket.EpicKey
+			case ticket.ParentKey != "":
+				// Subtasks written before the epic was known only point at their task
+				if parent, ok := byKey[ticket.ParentKey]; ok && parent.EpicKey != "" {
+					return parent.EpicKey
+				}
+			}
+		}
+	}
+
+	// Fall back to the focused epic
+	ctxManager := context.NewManager(dataDir)
+	if err := ctxManager.Load(); err == nil {
+		return ctxManager.GetEpicKey()
+	}
+	return ""
+}
+
+// postWorklogEntry posts a single ledger entry to Jira and records the outcome on it
+func postWorklogEntry(entry *worklog.Entry) error {
+	jiraClient, err := newKeyClient(entry.Key)
+	if err == nil {
+		entry.WorklogID, err = jiraClient.AddWorklog(entry.Key, entry.Started, entry.Duration, entry.Comment)
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+		return err
+	}
+	entry.Error = ""
+	return nil
+}
+
+// flushWorklog posts every entry that has not reached Jira yet
+func flushWorklog(ledger *worklog.Ledger) error {
+	pending := ledger.Pending()
+	if len(pending) == 0 {
+		fmt.Println("No pending worklog entries")
+		return nil
+	}
+
+	var posted, failed int
+	for _, entry := range pending {
+		if err := postWorklogEntry(entry); err != nil {
+			fmt.Printf("✗ %s %s (%s): %v\n", entry.Key, formatWorklogDuration(entry.Duration), entry.Started.Format("2006-01-02"), err)
+			failed++
+			continue
+		}
+		fmt.Printf("✓ %s %s (%s)\n", entry.Key, formatWorklogDuration(entry.Duration), entry.Started.Format("2006-01-02"))
+		posted++
+	}
+
+	if err := ledger.Save(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("Flush complete: %d posted, %d failed\n", posted, failed)
+	return nil
+}
+
+// printWorklogReport prints logged time for today or the current week, grouped by epic
+func printWorklogReport(ledger *worklog.Ledger, now time.Time, week bool) error {
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := from.AddDate(0, 0, 1)
+	title := "Time logged on " + from.Format("Mon 2006-01-02")
+	if week {
+		// Weeks start on Monday
+		offset := (int(from.Weekday()) + 6) % 7
+		from = from.AddDate(0, 0, -offset)
+		to = from.AddDate(0, 0, 7)
+		title = fmt.Sprintf("Time logged %s – %s", from.Format("Mon 2006-01-02"), to.AddDate(0, 0, -1).Format("Mon 2006-01-02"))
+	}
+
+	entries := ledger.Between(from, to)
+	fmt.Println(title)
+	if len(entries) == 0 {
+		fmt.Println("  No time logged")
+		return nil
+	}
+
+	byEpic := make(map[string][]*worklog.Entry)
+	var epics []string
+	for _, entry := range entries {
+		if _, ok := byEpic[entry.EpicKey]; !ok {
+			epics = append(epics, entry.EpicKey)
+		}
+		byEpic[entry.EpicKey] = append(byEpic[entry.EpicKey], entry)
+	}
+	sort.Strings(epics)
+
+	var total time.Duration
+	for _, epic := range epics {
+		var epicTotal time.Duration
+		perTicket := make(map[string]time.Duration)
+		var keys []string
+		for _, entry := range byEpic[epic] {
+			if _, ok := perTicket[entry.Key]; !ok {
+				keys = append(keys, entry.Key)
+			}
+			perTicket[entry.Key] += entry.Duration
+			epicTotal += entry.Duration
+		}
+		total += epicTotal
+
+		name := epic
+		if name == "" {
+			name = "(no epic)"
+		}
+		fmt.Printf("\n%-20s %8s\n", name, formatWorklogDuration(epicTotal))
+		for _, key := range keys {
+			fmt.Printf("  %-18s %8s\n", key, formatWorklogDuration(perTicket[key]))
+		}
+	}
+
+	fmt.Printf("\n%-20s %8s\n", "Total", formatWorklogDuration(total))
+	// Entries only reach Jira through --flush, which needs Jira as the tracker
+	if pending := len(ledger.Pending()); pending > 0 && usesJira() {
+		fmt.Printf("%d entries not yet posted to Jira, run 'jai log --flush'\n", pending)
+	}
+	return nil
+}
+
+// formatWorklogDuration formats a duration as e.g. 1h30m, dropping zero units
+func formatWorklogDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+
+	var parts []string
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes > 0 || hours == 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	return strings.Join(parts, "")
+}
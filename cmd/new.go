@@ -96,6 +96,11 @@ func runNew(cmd *cobra.Command, args []string) error {
 		Created:    time.Now(),
 		Updated:    time.Now(),
 	}
+	if parentKey != "" {
+		inheritProfile(dataDir, ticket, parentKey)
+	} else {
+		inheritProfile(dataDir, ticket, epicKey)
+	}
 
 	// Enrich with AI if enabled
 	if !noEnrich {
@@ -117,7 +122,7 @@ func runNew(cmd *cobra.Command, args []string) error {
 	}
 
 	// Add to epic file
-	parser := markdown.NewParser(dataDir)
+	parser := newParser(dataDir)
 	epicFilePath := parser.GetEpicFilePath(epicKey)
 
 	// Ensure epic file exists
@@ -132,6 +137,13 @@ func runNew(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Check Jira will accept the ticket before writing anything locally
+	if !noCreate {
+		if err := preflightCreate(ticket); err != nil {
+			return err
+		}
+	}
+
 	// Add ticket to file
 	if err := addTicketToEpicFile(parser, epicFilePath, ticket); err != nil {
 		return fmt.Errorf("failed to add ticket to epic file: %w", err)
@@ -146,17 +158,43 @@ func runNew(cmd *cobra.Command, args []string) error {
 
 	// Create Jira ticket if enabled
 	if !noCreate {
-		fmt.Println("Creating Jira ticket...")
-		if err := createJiraTicket(ticket); err != nil {
-			fmt.Printf("Warning: Failed to create Jira ticket: %v\n", err)
+		fmt.Printf("Creating %s ticket...\n", trackerName())
+		if err := createTrackerTicket(ticket); err != nil {
+			fmt.Printf("Warning: Failed to create %s ticket: %s\n", trackerName(), describeError(err))
+			// Keep the ticket with a placeholder key until 'jai push' can create it
+			if err := queueCreate(ticket, err); err != nil {
+				fmt.Printf("Warning: Failed to queue the ticket for 'jai push': %v\n", err)
+			}
 		} else {
-			fmt.Printf("Jira ticket created: %s\n", ticket.Key)
+			fmt.Printf("%s ticket created: %s\n", trackerName(), ticket.Key)
+		}
+
+		// Record the Jira (or placeholder) key on the ticket in the epic file
+		if ticket.Key != "" {
+			if err := setKeyInFile(parser, epicFilePath, ticket.Type, ticket.Title, ticket.Key); err != nil {
+				fmt.Printf("Warning: Failed to update epic file with the key: %v\n", err)
+			}
 		}
 	}
 
 	return nil
 }
 
+// setKeyInFile records a key on the keyless ticket of the given type and title in a markdown file
+func setKeyInFile(parser *markdown.Parser, filePath string, ticketType types.TicketType, title, key string) error {
+	mdFile, err := parser.ParseFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return rewriteTicketFile(parser, mdFile, func(ticket *types.Ticket) bool {
+		if ticket.Key != "" || ticket.Type != ticketType || ticket.Title != title {
+			return false
+		}
+		ticket.Key = key
+		return true
+	})
+}
+
 // addTicketToEpicFile adds a ticket to the epic markdown file
 func addTicketToEpicFile(parser *markdown.Parser, epicFilePath string, ticket *types.Ticket) error {
 	// Parse existing file
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jai/internal/convert"
+	"github.com/lunchboxsushi/jai/internal/jira"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// preflightCreate checks the ticket against the project's create metadata before anything
+// is written locally. Required fields jai wouldn't send are prompted for on a terminal;
+// otherwise the create is refused with the list of what's missing.
+func preflightCreate(ticket *types.Ticket) error {
+	// Only Jira projects have create metadata to check against
+	if !usesJira() {
+		return nil
+	}
+
+	config, err := loadTicketConfig(ticket)
+	if err != nil {
+		return err
+	}
+	jiraClient, err := newJiraClientWith(config)
+	if err != nil {
+		// Creating the ticket will fail and report this too, after the file is saved
+		return nil
+	}
+
+	missing, err := jiraClient.MissingRequiredFields(ticket)
+	if err != nil {
+		fmt.Printf("Warning: Skipping required field check: %s\n", describeError(err))
+		return nil
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if !isInteractive() {
+		var lines []string
+		for _, field := range missing {
+			lines = append(lines, "  - "+describeCreateField(field))
+		}
+		return fmt.Errorf("%s requires fields jai doesn't set:\n%s\nRun jai in a terminal to be prompted for them, or use --no-create to only save the ticket locally",
+			config.Jira.Project, strings.Join(lines, "\n"))
+	}
+
+	fmt.Printf("%s requires %d more field(s) to create this %s:\n", config.Jira.Project, len(missing), ticket.Type)
+	reader := bufio.NewReader(os.Stdin)
+	for _, field := range missing {
+		fmt.Printf("  %s: ", describeCreateField(field))
+		value, _ := reader.ReadString('\n')
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return fmt.Errorf("%s is required, creation cancelled", field.Name)
+		}
+		if err := setRequiredField(config, ticket, field, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setRequiredField stores a prompted value on the ticket, where CreateTicket will send it.
+// config is the ticket's own profile and project config.
+func setRequiredField(config *types.Config, ticket *types.Ticket, field jira.CreateField, value string) error {
+	switch field.ID {
+	case "priority":
+		ticket.Priority = value
+	case "labels":
+		ticket.Labels = convert.SplitList(value)
+	case "components":
+		ticket.Components = convert.SplitList(value)
+	case "assignee":
+		ticket.Assignee = value
+	case "duedate":
+		due, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return fmt.Errorf("%s must be a date like 2024-06-30", field.Name)
+		}
+		ticket.DueDate = &due
+	case "description":
+		ticket.Description = value
+	default:
+		name := customFieldName(config, field.ID)
+		if jira.CustomFieldID(config, name) == "" {
+			return fmt.Errorf("jai can't set %s; map it in jira.custom_fields or set a default in the Jira project", describeCreateField(field))
+		}
+		if ticket.CustomFields == nil {
+			ticket.CustomFields = make(map[string]interface{})
+		}
+		ticket.CustomFields[name] = value
+	}
+	return nil
+}
+
+// customFieldName returns the metadata name for a field: its friendly name from
+// jira.custom_fields if it has one, otherwise its Jira ID
+func customFieldName(config *types.Config, id string) string {
+	for name, mapped := range config.Jira.CustomFields {
+		if mapped == id {
+			return jira.CustomFieldLabel(name)
+		}
+	}
+	return id
+}
+
+// describeCreateField names a field along with its ID and allowed values
+func describeCreateField(field jira.CreateField) string {
+	description := fmt.Sprintf("%s (%s)", field.Name, field.ID)
+	if len(field.AllowedValues) > 0 {
+		description += fmt.Sprintf(" [%s]", strings.Join(field.AllowedValues, ", "))
+	}
+	return description
+}
+
+// isInteractive reports whether stdin is a terminal that can answer prompts
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
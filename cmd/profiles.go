@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/lunchboxsushi/jai/internal/jira"
+	"github.com/lunchboxsushi/jai/internal/types"
+	"github.com/spf13/viper"
+)
+
+var (
+	// profileName is the global --profile flag
+	profileName string
+
+	// projectOverride is the --project flag shared by epic and task
+	projectOverride string
+
+	// baseJiraSettings is the top-level jira block, before any profile is merged over it
+	baseJiraSettings map[string]interface{}
+)
+
+// activeProfile returns the profile picked with --profile or JAI_PROFILE, or "" for the
+// top-level jira settings
+func activeProfile() string {
+	profile := profileName
+	if profile == "" {
+		profile = os.Getenv("JAI_PROFILE")
+	}
+	return strings.ToLower(strings.TrimSpace(profile))
+}
+
+// profileNames returns the names of the profiles defined in the config, sorted
+func profileNames() []string {
+	var names []string
+	for name := range viper.GetStringMap("profiles") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profileJiraSettings returns the jira block of a profile, or false if it isn't defined
+func profileJiraSettings(profile string) (map[string]interface{}, bool) {
+	if !viper.IsSet("profiles." + profile) {
+		return nil, false
+	}
+	return copySettings(viper.GetStringMap("profiles." + profile + ".jira")), true
+}
+
+// applyProfile merges the active profile's jira settings over the top-level ones, so every
+// jira.* lookup sees the profile's values
+func applyProfile() error {
+	baseJiraSettings = copySettings(viper.GetStringMap("jira"))
+
+	profile := activeProfile()
+	if profile == "" {
+		return nil
+	}
+	settings, ok := profileJiraSettings(profile)
+	if !ok {
+		if names := profileNames(); len(names) > 0 {
+			return fmt.Errorf("unknown profile %q (available: %s)", profile, strings.Join(names, ", "))
+		}
+		return fmt.Errorf("unknown profile %q (no profiles are defined in the config)", profile)
+	}
+	return viper.MergeConfigMap(map[string]interface{}{"jira": settings})
+}
+
+// profileViper returns the settings to read a profile's jira config from. The active
+// profile is already merged into the global settings; any other one is layered over a
+// copy of the top-level jira block.
+func profileViper(profile string) (*viper.Viper, error) {
+	profile = strings.ToLower(profile)
+	if profile == activeProfile() {
+		return viper.GetViper(), nil
+	}
+
+	v := viper.New()
+	if err := v.MergeConfigMap(map[string]interface{}{"jira": copySettings(baseJiraSettings)}); err != nil {
+		return nil, fmt.Errorf("failed to load jira settings: %w", err)
+	}
+	if profile == "" {
+		return v, nil
+	}
+
+	settings, ok := profileJiraSettings(profile)
+	if !ok {
+		return nil, fmt.Errorf("ticket belongs to profile %q, which is not defined in the config", profile)
+	}
+	if err := v.MergeConfigMap(map[string]interface{}{"jira": settings}); err != nil {
+		return nil, fmt.Errorf("failed to load profile %s: %w", profile, err)
+	}
+	return v, nil
+}
+
+// copySettings deep-copies a settings map, since merging writes into the maps it is given
+func copySettings(settings map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = copySettings(nested)
+		}
+		copied[key] = value
+	}
+	return copied
+}
+
+// jiraToken returns the API token for a profile: JAI_JIRA_TOKEN_<PROFILE> if set, otherwise
+// JAI_JIRA_TOKEN
+func jiraToken(profile string) string {
+	if profile != "" {
+		if token := os.Getenv(profileEnv("JAI_JIRA_TOKEN", profile)); token != "" {
+			return token
+		}
+	}
+	return os.Getenv("JAI_JIRA_TOKEN")
+}
+
+// profileEnv returns the per-profile variant of an environment variable, e.g.
+// JAI_JIRA_TOKEN_WORK for the "work" profile
+func profileEnv(name, profile string) string {
+	suffix := strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(profile))
+	return name + "_" + suffix
+}
+
+// loadTicketConfig builds the Jira config for the profile a ticket belongs to, pointed at
+// the ticket's project if it overrides the configured one
+func loadTicketConfig(ticket *types.Ticket) (*types.Config, error) {
+	config, err := loadJiraConfigFor(ticket.Profile)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Project != "" {
+		config.Jira.Project = ticket.Project
+	}
+	return config, nil
+}
+
+// newTicketClient creates a Jira client for the profile and project a ticket belongs to
+func newTicketClient(ticket *types.Ticket) (*jira.Client, error) {
+	config, err := loadTicketConfig(ticket)
+	if err != nil {
+		return nil, err
+	}
+	return newJiraClientWith(config)
+}
+
+// profileClients creates Jira clients on demand, one per profile and project, for commands
+// that work across tickets from several profiles
+type profileClients struct {
+	clients map[string]*jira.Client
+	errs    map[string]error
+}
+
+// newProfileClients creates an empty client cache
+func newProfileClients() *profileClients {
+	return &profileClients{
+		clients: make(map[string]*jira.Client),
+		errs:    make(map[string]error),
+	}
+}
+
+// get returns the client for a ticket's profile and project, creating it on first use
+func (pc *profileClients) get(ticket *types.Ticket) (*jira.Client, error) {
+	key := strings.ToLower(ticket.Profile) + "/" + strings.ToUpper(ticket.Project)
+	if client, ok := pc.clients[key]; ok {
+		return client, nil
+	}
+	if err, ok := pc.errs[key]; ok {
+		return nil, err
+	}
+
+	client, err := newTicketClient(ticket)
+	if err != nil {
+		pc.errs[key] = err
+		return nil, err
+	}
+	pc.clients[key] = client
+	return client, nil
+}
+
+// newKeyClient creates a Jira client for an existing ticket, using the profile and project
+// of the local copy if there is one and the active profile otherwise
+func newKeyClient(key string) (*jira.Client, error) {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return nil, err
+	}
+	if _, ticket, err := findTicketByKey(dataDir, newParser(dataDir), key); err == nil {
+		return newTicketClient(ticket)
+	}
+	return newJiraClient()
+}
+
+// inheritProfile copies the profile and project of a new ticket's local parent onto it,
+// so children land where their epic lives. Without a local parent the active profile
+// applies. --project overrides the project either way.
+func inheritProfile(dataDir string, ticket *types.Ticket, parentKey string) {
+	ticket.Profile = activeProfile()
+	if parentKey != "" {
+		if _, parent, err := findTicketByKey(dataDir, newParser(dataDir), parentKey); err == nil {
+			ticket.Profile = parent.Profile
+			ticket.Project = parent.Project
+		}
+	}
+	if projectOverride != "" {
+		ticket.Project = strings.ToUpper(projectOverride)
+	}
+}
+
+// profileUsername returns the Jira username configured for a profile
+func profileUsername(profile string) string {
+	v, err := profileViper(profile)
+	if err != nil {
+		return viper.GetString("jira.username")
+	}
+	return v.GetString("jira.username")
+}
+
+// inProfile reports whether a ticket belongs to the active profile. Without an active
+// profile every ticket does.
+func inProfile(ticket types.Ticket) bool {
+	profile := activeProfile()
+	return profile == "" || strings.EqualFold(ticket.Profile, profile)
+}
+
+// belongsToProfile reports whether a ticket belongs to exactly the given profile, "" being
+// the top-level jira settings. Commands bound to one Jira instance use it instead of
+// inProfile, which lets every ticket through when no profile is active.
+func belongsToProfile(ticket types.Ticket, profile string) bool {
+	return strings.EqualFold(ticket.Profile, profile)
+}
+
+// filterProfile keeps the tickets that belong to the active profile
+func filterProfile(tickets []types.Ticket) []types.Ticket {
+	if activeProfile() == "" {
+		return tickets
+	}
+	var filtered []types.Ticket
+	for _, ticket := range tickets {
+		if inProfile(ticket) {
+			filtered = append(filtered, ticket)
+		}
+	}
+	return filtered
+}
+
+// profileTag labels a ticket from a profile other than the active one, e.g. " @work"
+func profileTag(ticket types.Ticket) string {
+	if ticket.Profile == "" || strings.EqualFold(ticket.Profile, activeProfile()) {
+		return ""
+	}
+	return " @" + ticket.Profile
+}
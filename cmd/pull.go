@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lunchboxsushi/jai/internal/jira"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var pullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Import or refresh every Jira issue matching a JQL query",
+	Long: `Fetch every issue matching a JQL query and write it into the tickets directory.
+Results are paged through in full, so large boards are not truncated. New issues
+get their own markdown file and issues that already exist locally have their
+status, priority, parent metadata and comments refreshed.
+
+Examples:
+  jai pull --jql "sprint in openSprints() AND project = SRE"
+  jai pull --jql "assignee = currentUser()" --limit 50
+  jai pull --jql "project = SRE" --page-size 50`,
+	RunE: runPull,
+}
+
+var (
+	pullJQL      string
+	pullPageSize int
+	pullLimit    int
+)
+
+func init() {
+	pullCmd.Flags().StringVar(&pullJQL, "jql", "", "JQL query selecting the issues to pull")
+	pullCmd.Flags().IntVar(&pullPageSize, "page-size", 0, "Issues fetched per request (default jira.page_size or 100)")
+	pullCmd.Flags().IntVar(&pullLimit, "limit", 0, "Maximum number of issues to pull (0 for no limit)")
+	pullCmd.MarkFlagRequired("jql")
+	rootCmd.AddCommand(pullCmd)
+}
+
+func runPull(cmd *cobra.Command, args []string) error {
+	if err := requireJira("pull"); err != nil {
+		return err
+	}
+
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
+	}
+
+	jiraClient, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	pageSize := pullPageSize
+	if pageSize <= 0 {
+		pageSize = viper.GetInt("jira.page_size")
+	}
+
+	fmt.Printf("Searching Jira: %s\n", pullJQL)
+	tickets, err := jiraClient.SearchAll(pullJQL, jira.SearchOptions{
+		PageSize: pageSize,
+		Limit:    pullLimit,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Found %d issues\n", len(tickets))
+
+	parser := newParser(dataDir)
+	importer, err := newTicketImporter(dataDir, parser)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, ticket := range tickets {
+		if err := importer.importTicket(ticket); err != nil {
+			fmt.Printf("✗ %s: %v\n", ticket.Key, err)
+			failed++
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Pull complete: %d created, %d updated, %d unchanged, %d failed\n",
+		importer.stats.created, importer.stats.updated, importer.stats.unchanged, failed)
+	return nil
+}
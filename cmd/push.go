@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lunchboxsushi/jai/internal/context"
+	"github.com/lunchboxsushi/jai/internal/markdown"
+	"github.com/lunchboxsushi/jai/internal/outbox"
+	"github.com/lunchboxsushi/jai/internal/types"
+	"github.com/lunchboxsushi/jai/internal/worklog"
+	"github.com/spf13/cobra"
+)
+
+var pushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Replay Jira creates and updates that failed earlier",
+	Long: `Replay the Jira writes queued in the outbox (outbox.json in the data directory).
+
+When Jira can't be reached while creating a ticket, the ticket is saved locally with a
+placeholder key such as PENDING-3 and queued. It can be focused and given tasks or
+subtasks as usual. Pushing creates the queued tickets parents first, then replaces the
+placeholder everywhere it is used: the ticket's file name and Key line, the EpicKey and
+ParentKey lines of its children, the current focus and the worklog ledger. The new key
+is recorded in the outbox as soon as the ticket is created, so if updating the local
+files fails, the next push finishes that instead of creating the ticket again.
+
+Updates that 'jai sync' failed to push are queued too, and pushed again unless the
+ticket has changed in Jira since; those are left for 'jai sync' to merge.
+
+'jai status' shows how many operations are waiting.
+
+Examples:
+  jai push                    # Replay everything in the outbox
+  jai push --dry-run          # List what is queued without contacting Jira`,
+	Args: cobra.NoArgs,
+	RunE: runPush,
+}
+
+var pushDryRun bool
+
+// errWaitingOnParent marks a queued create whose parent hasn't been created yet
+var errWaitingOnParent = errors.New("waiting for its parent to be created")
+
+func init() {
+	pushCmd.Flags().BoolVar(&pushDryRun, "dry-run", false, "List the queued operations without pushing them")
+	rootCmd.AddCommand(pushCmd)
+}
+
+func runPush(cmd *cobra.Command, args []string) error {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
+	}
+
+	ob := outbox.New(dataDir)
+	if err := ob.Load(); err != nil {
+		return err
+	}
+	if ob.Len() == 0 {
+		fmt.Println("Nothing to push")
+		return nil
+	}
+
+	if pushDryRun {
+		for _, op := range ob.Pending() {
+			fmt.Printf("%s %s", op.Kind, op.Key)
+			if op.Created != "" {
+				fmt.Printf(" (created as %s, local files not updated yet)", op.Created)
+			}
+			if op.Error != "" {
+				fmt.Printf(" (last error: %s)", op.Error)
+			}
+			fmt.Println()
+		}
+		return nil
+	}
+
+	parser := newParser(dataDir)
+	ctxManager := context.NewManager(dataDir)
+	if err := ctxManager.Load(); err != nil {
+		return fmt.Errorf("failed to load context: %w", err)
+	}
+
+	var created, updated, waiting, failed int
+	for _, op := range ob.Pending() {
+		switch op.Kind {
+		case outbox.KindCreate:
+			err = replayCreate(dataDir, parser, ctxManager, ob, op)
+		case outbox.KindUpdate:
+			err = replayUpdate(dataDir, parser, op)
+		default:
+			err = fmt.Errorf("unknown operation %q", op.Kind)
+		}
+
+		switch {
+		case errors.Is(err, errWaitingOnParent):
+			fmt.Printf("… %s: %v\n", op.Key, err)
+			waiting++
+		case err != nil:
+			fmt.Printf("✗ %s: %s\n", op.Key, describeError(err))
+			op.Attempts++
+			op.Error = describeError(err)
+			failed++
+		default:
+			if op.Kind == outbox.KindCreate {
+				created++
+			} else {
+				updated++
+			}
+			ob.Remove(op)
+		}
+
+		// Save after every operation so a crash can't replay a create that went through
+		if err := ob.Save(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Push complete: %d created, %d updated, %d failed, %d still queued\n", created, updated, failed, ob.Len())
+	if waiting > 0 || failed > 0 {
+		fmt.Println("Run 'jai push' again once the failures above are fixed")
+	}
+	return nil
+}
+
+// replayCreate creates a queued ticket in Jira and swaps its placeholder key for the real one.
+// If an earlier push created the ticket but failed to update the local files, only the
+// local update is retried.
+func replayCreate(dataDir string, parser *markdown.Parser, ctxManager *context.Manager, ob *outbox.Outbox, op *outbox.Operation) error {
+	placeholder := op.Key
+	path, ticket, err := findTicketByKey(dataDir, parser, placeholder)
+	if err != nil && op.Created != "" {
+		// The ticket's own file was rewritten before the last push failed
+		path, ticket, err = findTicketByKey(dataDir, parser, op.Created)
+	}
+	if err != nil {
+		fmt.Printf("Warning: %s is no longer in the tickets directory, dropping it from the outbox\n", placeholder)
+		return nil
+	}
+	ticket.Title = parser.RemoveJiraKey(ticket.Title)
+
+	if op.Created != "" {
+		ticket.Key = op.Created
+		fmt.Printf("+ %s → %s: %s (already created, updating local files)\n", placeholder, ticket.Key, ticket.Title)
+	} else {
+		if outbox.IsPlaceholder(ticket.EpicKey) || outbox.IsPlaceholder(ticket.ParentKey) {
+			return errWaitingOnParent
+		}
+
+		ticket.Key = ""
+		if err := createTrackerTicket(ticket); err != nil {
+			return err
+		}
+		fmt.Printf("+ %s → %s: %s\n", placeholder, ticket.Key, ticket.Title)
+
+		// Record the new key before touching local files, so a failure below can't lead to
+		// the ticket being created twice
+		op.Created = ticket.Key
+		if err := ob.Save(); err != nil {
+			return fmt.Errorf("created as %s, but failed to record it in the outbox: %w", ticket.Key, err)
+		}
+	}
+
+	if err := replacePlaceholder(dataDir, parser, placeholder, ticket); err != nil {
+		return fmt.Errorf("created as %s, but failed to update local files: %w", ticket.Key, err)
+	}
+	if filepath.Base(path) == ticketFileName(placeholder, ticket.Title) {
+		newPath := filepath.Join(filepath.Dir(path), ticketFileName(ticket.Key, ticket.Title))
+		if err := os.Rename(path, newPath); err != nil {
+			fmt.Printf("Warning: Failed to rename %s: %v\n", path, err)
+		}
+	}
+
+	// Anything else still pointing at the placeholder follows the ticket to its new key
+	ob.RenameKey(placeholder, ticket.Key)
+
+	ctx := ctxManager.Get()
+	if ctx.EpicKey == placeholder || ctx.TaskKey == placeholder || ctx.SubtaskKey == placeholder {
+		if err := ctxManager.SetFullContext(
+			renameKey(ctx.EpicKey, placeholder, ticket.Key), ctx.EpicID,
+			renameKey(ctx.TaskKey, placeholder, ticket.Key), ctx.TaskID,
+			renameKey(ctx.SubtaskKey, placeholder, ticket.Key), ctx.SubtaskID,
+		); err != nil {
+			fmt.Printf("Warning: Failed to update focus: %v\n", err)
+		}
+	}
+
+	ledger := worklog.NewLedger(dataDir)
+	if err := ledger.Load(); err == nil && ledger.RenameKey(placeholder, ticket.Key) {
+		if err := ledger.Save(); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// replacePlaceholder rewrites every ticket file that refers to a placeholder key, as the
+// ticket's own key or as a child's epic or parent, to use the ticket's real key
+func replacePlaceholder(dataDir string, parser *markdown.Parser, placeholder string, created *types.Ticket) error {
+	mdFiles, err := findTicketFiles(dataDir, parser)
+	if err != nil {
+		return err
+	}
+
+	for _, mdFile := range mdFiles {
+		err := rewriteTicketFile(parser, mdFile, func(ticket *types.Ticket) bool {
+			changed := false
+			if ticket.Key == placeholder {
+				ticket.Key = created.Key
+				ticket.Attachments = created.Attachments
+				changed = true
+			}
+			if ticket.EpicKey == placeholder {
+				ticket.EpicKey = created.Key
+				changed = true
+			}
+			if ticket.ParentKey == placeholder {
+				ticket.ParentKey = created.Key
+				changed = true
+			}
+			return changed
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewriteTicketFile applies a change to the tickets in a markdown file and writes it back
+// if any of them changed. Task and subtask files are regenerated the way they were
+// created, so their epic header follows the metadata.
+func rewriteTicketFile(parser *markdown.Parser, mdFile *types.MarkdownFile, change func(*types.Ticket) bool) error {
+	changed := false
+	for i := range mdFile.Tickets {
+		if change(&mdFile.Tickets[i]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	var content string
+	if len(mdFile.Tickets) == 1 {
+		// Headers are regenerated with the key, so drop the one parsed into the title
+		ticket := &mdFile.Tickets[0]
+		ticket.Title = parser.RemoveJiraKey(ticket.Title)
+		switch ticket.Type {
+		case types.TicketTypeTask:
+			content = generateTaskMarkdown(parser, ticket)
+		case types.TicketTypeSubtask:
+			content = generateSubtaskMarkdown(parser, ticket)
+		}
+	}
+	if content == "" {
+		return parser.WriteFile(mdFile.Path, mdFile.Tickets)
+	}
+	if err := os.WriteFile(mdFile.Path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", mdFile.Path, err)
+	}
+	return nil
+}
+
+// renameKey returns newKey if key is oldKey, and key otherwise
+func renameKey(key, oldKey, newKey string) string {
+	if key == oldKey {
+		return newKey
+	}
+	return key
+}
+
+// replayUpdate pushes a ticket whose update failed, unless Jira has changed it since
+func replayUpdate(dataDir string, parser *markdown.Parser, op *outbox.Operation) error {
+	_, ticket, err := findTicketByKey(dataDir, parser, op.Key)
+	if err != nil {
+		fmt.Printf("Warning: %s is no longer in the tickets directory, dropping it from the outbox\n", op.Key)
+		return nil
+	}
+
+	backend, err := newTicketBackend(ticket)
+	if err != nil {
+		return err
+	}
+
+	remote, err := backend.GetTicket(op.Key)
+	if err != nil {
+		return err
+	}
+	if remote.Updated.After(op.Queued) {
+		fmt.Printf("Warning: %s changed in Jira after the update was queued; run 'jai sync' to merge it\n", op.Key)
+		return nil
+	}
+
+	ticket.Title = parser.RemoveJiraKey(ticket.Title)
+	if err := backend.UpdateTicket(ticket); err != nil {
+		return err
+	}
+	fmt.Printf("↑ %s: %s\n", ticket.Key, ticket.Title)
+	return nil
+}
+
+// queueCreate records a ticket Jira couldn't create in the outbox and gives it a
+// placeholder key, so it can be focused and given children until 'jai push' creates it
+func queueCreate(ticket *types.Ticket, cause error) error {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
+	}
+
+	ob := outbox.New(dataDir)
+	if err := ob.Load(); err != nil {
+		return err
+	}
+	key := ob.NextPlaceholder()
+	ob.Add(outbox.KindCreate, key, ticket.Type, describeError(cause))
+	if err := ob.Save(); err != nil {
+		return err
+	}
+
+	ticket.Key = key
+	fmt.Printf("Saved as %s; run 'jai push' to create it once Jira is reachable\n", key)
+	return nil
+}
+
+// pendingOperations returns how many Jira writes are waiting in the outbox
+func pendingOperations(dataDir string) int {
+	ob := outbox.New(dataDir)
+	if err := ob.Load(); err != nil {
+		return 0
+	}
+	return ob.Len()
+}
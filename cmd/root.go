@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -32,7 +33,11 @@ Key features:
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if err != nil && jiraErrorHint(err) != "" {
+		return errors.New(describeError(err))
+	}
+	return err
 }
 
 func init() {
@@ -41,6 +46,7 @@ func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.jai/config.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Jira profile from the config's profiles section (default is $JAI_PROFILE)")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -67,4 +73,7 @@ func initConfig() {
 			fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 		}
 	}
+
+	// Layer the selected profile's jira settings over the top-level ones
+	cobra.CheckErr(applyProfile())
 }
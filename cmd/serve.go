@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/lunchboxsushi/jai/internal/jira"
+	"github.com/lunchboxsushi/jai/internal/markdown"
+	"github.com/lunchboxsushi/jai/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Receive Jira webhooks and keep local tickets up to date",
+	Long: `Run an HTTP server that receives Jira webhooks and applies them to the local
+tickets as they arrive, so status changes made by teammates show up in 'jai status'
+without running 'jai sync'.
+
+Point a Jira webhook at the server for these events:
+  - Issue created     new tasks and subtasks under a local epic or task are imported
+  - Issue updated     status, priority, assignee, epic, parent and sprint are refreshed,
+                      and cleared locally when they are cleared in Jira
+  - Comment created   the comment is added to the ticket's comments
+
+Only the metadata and comments of tickets already in the tickets directory are changed;
+descriptions, links and custom fields are left to 'jai sync'. Webhooks only touch the
+tickets of the served profile, since the others live on other Jira instances; without
+--profile that means the tickets that don't belong to a profile. Run one server per
+profile to cover them all.
+
+Every request must carry the shared secret from JAI_WEBHOOK_SECRET (or
+JAI_WEBHOOK_SECRET_<PROFILE> for a profile). Set it as the webhook's secret in Jira
+Cloud, which signs each request with it, or append ?secret=<value> to the webhook URL
+where requests can't be signed. Requests without a valid secret are rejected.
+
+Examples:
+  jai serve                        # Listen on :8787
+  jai serve --listen 127.0.0.1:9000
+  jai serve --profile work         # Apply webhooks to tickets of the work profile`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+var serveListen string
+
+// maxWebhookBody caps the size of a webhook payload
+const maxWebhookBody = 10 << 20
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", ":8787", "Address to listen on")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if err := requireJira("serve"); err != nil {
+		return err
+	}
+
+	secret := webhookSecret(activeProfile())
+	if secret == "" {
+		return fmt.Errorf("no webhook secret set (set JAI_WEBHOOK_SECRET to the secret configured on the Jira webhook)")
+	}
+
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
+	}
+
+	jiraClient, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	handler := &webhookHandler{
+		dataDir: dataDir,
+		parser:  newParser(dataDir),
+		client:  jiraClient,
+		profile: activeProfile(),
+		secret:  secret,
+	}
+	server := &http.Server{
+		Addr:              serveListen,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Listening for Jira webhooks on %s (Ctrl+C to stop)\n", serveListen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve webhooks: %w", err)
+	}
+	fmt.Println("Stopped")
+	return nil
+}
+
+// webhookSecret returns the shared webhook secret for a profile: JAI_WEBHOOK_SECRET_<PROFILE>
+// if set, otherwise JAI_WEBHOOK_SECRET
+func webhookSecret(profile string) string {
+	if profile != "" {
+		if secret := os.Getenv(profileEnv("JAI_WEBHOOK_SECRET", profile)); secret != "" {
+			return secret
+		}
+	}
+	return os.Getenv("JAI_WEBHOOK_SECRET")
+}
+
+// webhookHandler applies Jira webhooks to the local tickets
+type webhookHandler struct {
+	dataDir string
+	parser  *markdown.Parser
+	client  *jira.Client
+	profile string // The profile whose Jira instance sends the webhooks
+	secret  string
+
+	// Requests are applied one at a time, since they rewrite the same files
+	mu sync.Mutex
+}
+
+// ServeHTTP verifies and applies a single webhook request
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if !jira.VerifyWebhook(r, body, h.secret) {
+		fmt.Printf("✗ Rejected webhook from %s: missing or invalid secret\n", r.RemoteAddr)
+		http.Error(w, "invalid secret", http.StatusUnauthorized)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	event, err := h.client.ParseWebhook(body)
+	if err != nil {
+		fmt.Printf("✗ %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch event.Event {
+	case jira.EventIssueCreated, jira.EventIssueUpdated, jira.EventCommentCreated:
+		if err := h.apply(event); err != nil {
+			fmt.Printf("✗ %s: %v\n", event.Ticket.Key, err)
+			http.Error(w, "failed to apply webhook", http.StatusInternalServerError)
+			return
+		}
+	default:
+		if verbose {
+			fmt.Printf("- Ignored %s for %s\n", event.Event, event.Ticket.Key)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apply updates the local copy of the webhook's ticket, or imports it if it was just
+// created under a ticket that is tracked locally
+func (h *webhookHandler) apply(event *jira.WebhookEvent) error {
+	remote := event.Ticket
+	path, ticket, err := findTicketByKey(h.dataDir, h.parser, remote.Key)
+	if err != nil {
+		if event.Event == jira.EventIssueCreated {
+			return h.importCreated(remote)
+		}
+		// Not a ticket we track
+		return nil
+	}
+	// Only tickets of the served profile come from this Jira instance
+	if !belongsToProfile(*ticket, h.profile) {
+		return nil
+	}
+
+	// Comment events only carry a few of the issue's fields
+	changes := refreshMetadata(ticket, remote, event.Event == jira.EventCommentCreated)
+	if event.Comment != nil && !hasComment(ticket.Comments, *event.Comment) {
+		ticket.Comments = append(ticket.Comments, *event.Comment)
+		changes = append(changes, fmt.Sprintf("comment from %s", event.Comment.Author))
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if err := h.parser.UpdateTicket(path, *ticket); err != nil {
+		return fmt.Errorf("failed to update %s: %w", path, err)
+	}
+	fmt.Printf("↻ %s: %s\n", ticket.Key, strings.Join(changes, ", "))
+	return nil
+}
+
+// importCreated imports a new ticket whose epic or parent is tracked locally
+func (h *webhookHandler) importCreated(remote *types.Ticket) error {
+	if remote.Type == types.TicketTypeEpic {
+		return nil
+	}
+
+	parentKey := remote.EpicKey
+	if remote.Type == types.TicketTypeSubtask {
+		parentKey = remote.ParentKey
+	}
+	if parentKey == "" {
+		return nil
+	}
+	_, parent, err := findTicketByKey(h.dataDir, h.parser, parentKey)
+	if err != nil || !belongsToProfile(*parent, h.profile) {
+		return nil
+	}
+
+	// Subtasks belong to their task's epic, as they do when the epic is imported
+	if remote.Type == types.TicketTypeSubtask && remote.EpicKey == "" {
+		remote.EpicKey = parent.EpicKey
+	}
+
+	importer, err := newTicketImporter(h.dataDir, h.parser)
+	if err != nil {
+		return err
+	}
+	return importer.importTicket(remote)
+}
+
+// refreshMetadata copies the Jira-owned metadata of a remote ticket onto the local one and
+// describes what changed. A field left empty on a full remote ticket was cleared in Jira,
+// e.g. unassigned or taken out of its sprint, and is cleared locally too. Partial tickets,
+// such as those in comment webhooks, leave out fields they don't carry, so their empty
+// fields are skipped.
+func refreshMetadata(ticket, remote *types.Ticket, partial bool) []string {
+	var changes []string
+	for _, field := range []struct {
+		name   string
+		local  *string
+		remote string
+		// clears is whether an empty remote value means the field was unset
+		clears bool
+	}{
+		{"Status", &ticket.Status, remote.Status, false},
+		{"Priority", &ticket.Priority, remote.Priority, true},
+		{"Assignee", &ticket.Assignee, remote.Assignee, true},
+		// Subtasks inherit their epic locally, so only a task's epic can be cleared
+		{"Epic", &ticket.EpicKey, remote.EpicKey, ticket.Type == types.TicketTypeTask},
+		{"Parent", &ticket.ParentKey, remote.ParentKey, false},
+		{"Sprint", &ticket.Sprint, remote.Sprint, true},
+	} {
+		if *field.local == field.remote {
+			continue
+		}
+		if field.remote == "" && (partial || !field.clears) {
+			continue
+		}
+		switch {
+		case *field.local == "":
+			changes = append(changes, fmt.Sprintf("%s: %s", field.name, field.remote))
+		case field.remote == "":
+			changes = append(changes, fmt.Sprintf("%s: %s → none", field.name, *field.local))
+		default:
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", field.name, *field.local, field.remote))
+		}
+		*field.local = field.remote
+	}
+	return changes
+}
+
+// hasComment reports whether a comment is already in the list
+func hasComment(comments []types.Comment, comment types.Comment) bool {
+	for _, existing := range comments {
+		if existing.Author == comment.Author && sameMarkdown(existing.Body, comment.Body) {
+			return true
+		}
+	}
+	return false
+}
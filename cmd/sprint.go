@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	treepkg "github.com/charmbracelet/lipgloss/tree"
+	"github.com/lunchboxsushi/jai/internal/context"
+	"github.com/lunchboxsushi/jai/internal/jira"
+	"github.com/lunchboxsushi/jai/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var sprintCmd = &cobra.Command{
+	Use:   "sprint [active|next|name]",
+	Short: "Show the tickets in a sprint as a tree",
+	Long: `Show the tickets in the active sprint (or the next one, or one picked by name) of
+the board set in jira.board_id, grouped by epic and task like 'jai list'. Tickets
+are read from Jira, so ones that aren't tracked locally are included too.
+
+Examples:
+  jai sprint                  # The active sprint
+  jai sprint next             # The next planned sprint
+  jai sprint "Sprint 42"      # A sprint by name`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSprint,
+}
+
+// sprintQuery is the --sprint flag shared by epic, task and subtask
+var sprintQuery string
+
+func init() {
+	rootCmd.AddCommand(sprintCmd)
+}
+
+func runSprint(cmd *cobra.Command, args []string) error {
+	if err := requireJira("sprint"); err != nil {
+		return err
+	}
+
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
+	}
+
+	jiraClient, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	query := "active"
+	if len(args) > 0 {
+		query = args[0]
+	}
+	sprint, err := jiraClient.FindSprint(query)
+	if err != nil {
+		return err
+	}
+
+	tickets, err := jiraClient.GetSprintTickets(sprint.ID)
+	if err != nil {
+		return err
+	}
+
+	ctxManager := context.NewManager(dataDir)
+	if err := ctxManager.Load(); err != nil {
+		return fmt.Errorf("failed to load context: %w", err)
+	}
+	localTickets, _ := findAllTickets(dataDir, newParser(dataDir))
+
+	fmt.Println(buildSprintTree(sprint, tickets, localTickets, ctxManager.Get()).String())
+	return nil
+}
+
+// buildSprintTree groups a sprint's tickets under their epics and tasks. Epics that aren't
+// in the sprint themselves are still shown as headings, using the local copy if there is one.
+func buildSprintTree(sprint *jira.Sprint, tickets []*types.Ticket, localTickets []types.Ticket, ctx *types.Context) *treepkg.Tree {
+	title := fmt.Sprintf("🏃 %s", sprint.Name)
+	if sprint.StartDate != nil && sprint.EndDate != nil {
+		title += fmt.Sprintf(" (%s – %s)", sprint.StartDate.Format("Jan 2"), sprint.EndDate.Format("Jan 2"))
+	}
+	tree := treepkg.New().Root(title)
+	tree.Enumerator(treepkg.RoundedEnumerator)
+
+	var epics, tasks, subtasks, orphanTasks []types.Ticket
+	epicKeys := make(map[string]bool)
+	for _, t := range tickets {
+		ticket := *t
+		if ticket.Status != "" {
+			ticket.Title = fmt.Sprintf("%s · %s", strings.TrimSpace(ticket.Title), ticket.Status)
+		}
+		switch ticket.Type {
+		case types.TicketTypeEpic:
+			epics = append(epics, ticket)
+			epicKeys[ticket.Key] = true
+		case types.TicketTypeTask:
+			if ticket.EpicKey == "" {
+				orphanTasks = append(orphanTasks, ticket)
+			} else {
+				tasks = append(tasks, ticket)
+			}
+		case types.TicketTypeSubtask:
+			subtasks = append(subtasks, ticket)
+		}
+	}
+
+	// Add headings for epics whose tasks are in the sprint
+	for _, task := range tasks {
+		if epicKeys[task.EpicKey] {
+			continue
+		}
+		epicKeys[task.EpicKey] = true
+		epic := types.Ticket{Key: task.EpicKey, Type: types.TicketTypeEpic, Title: task.EpicKey}
+		for _, local := range localTickets {
+			if local.Key == task.EpicKey {
+				epic = local
+				break
+			}
+		}
+		epics = append(epics, epic)
+	}
+
+	for _, epic := range epics {
+		tree.Child(buildEpicSubtree(epic, tasks, subtasks, ctx))
+	}
+	if len(orphanTasks) > 0 {
+		orphanTree := treepkg.New().Root("🏴‍☠️ Orphan Tasks")
+		for _, orphanTask := range orphanTasks {
+			orphanTree.Child(buildTaskSubtree(orphanTask, subtasks, ctx))
+		}
+		tree.Child(orphanTree)
+	}
+	if len(tickets) == 0 {
+		tree.Child("No tickets in this sprint")
+	}
+
+	return tree
+}
+
+// addToSprint moves a newly created ticket into the sprint picked with --sprint and
+// records it in the ticket's metadata. Subtasks follow their parent, so the parent task
+// is moved instead.
+func addToSprint(jiraClient *jira.Client, ticket *types.Ticket, query string) {
+	sprint, err := jiraClient.FindSprint(query)
+	if err != nil {
+		fmt.Printf("Warning: Not adding %s to a sprint: %s\n", ticket.Key, describeError(err))
+		return
+	}
+
+	key := ticket.Key
+	if ticket.Type == types.TicketTypeSubtask && ticket.ParentKey != "" {
+		key = ticket.ParentKey
+	}
+	if err := jiraClient.MoveToSprint(sprint.ID, key); err != nil {
+		fmt.Printf("Warning: %s\n", describeError(err))
+		return
+	}
+
+	ticket.Sprint = sprint.Name
+	if key != ticket.Key {
+		fmt.Printf("Moved parent %s to %s (subtasks follow their parent)\n", key, sprint.Name)
+	} else {
+		fmt.Printf("Added %s to %s\n", key, sprint.Name)
+	}
+}
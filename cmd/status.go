@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/lunchboxsushi/jai/internal/context"
+	"github.com/lunchboxsushi/jai/internal/jira"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -52,6 +53,11 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	fmt.Println()
 
+	// Show Jira writes waiting to be replayed
+	if pending := pendingOperations(dataDir); pending > 0 {
+		fmt.Printf("📤 %d operation(s) waiting in the outbox, run 'jai push' to send them to Jira\n\n", pending)
+	}
+
 	// Show configuration status
 	if showConfigDetails {
 		fmt.Println("Configuration:")
@@ -64,23 +70,15 @@ func runStatus(cmd *cobra.Command, args []string) error {
 // showConfigStatus shows the status of configuration
 func showConfigStatus() {
 	// Check Jira config
-	jiraURL := viper.GetString("jira.url")
-	jiraUser := viper.GetString("jira.username")
-	jiraToken := os.Getenv("JAI_JIRA_TOKEN")
-	jiraProject := viper.GetString("jira.project")
-
-	if jiraURL != "" && jiraUser != "" && jiraToken != "" {
-		fmt.Printf("  Jira: ✓ Connected to %s (Project: %s)\n", jiraURL, jiraProject)
+	config := loadJiraConfig()
+	if !usesJira() {
+		fmt.Printf("  Tracker: ✓ %s\n", trackerName())
+	} else if missing := jira.CheckAuthConfig(config); len(missing) == 0 {
+		fmt.Printf("  Jira: ✓ Connected to %s (Project: %s, auth: %s)\n", config.Jira.URL, config.Jira.Project, jira.AuthMode(config))
 	} else {
 		fmt.Println("  Jira: ✗ Not configured")
-		if jiraURL == "" {
-			fmt.Println("    - URL not set")
-		}
-		if jiraUser == "" {
-			fmt.Println("    - Username not set")
-		}
-		if jiraToken == "" {
-			fmt.Println("    - Token not set (set JAI_JIRA_TOKEN environment variable)")
+		for _, problem := range missing {
+			fmt.Printf("    - %s\n", problem)
 		}
 	}
 
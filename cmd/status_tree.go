@@ -40,7 +40,7 @@ func renderStatusTree(ctxManager *context.Manager) error {
 		dataDir = filepath.Join(home, ".local", "share", "jai")
 	}
 
-	parser := markdown.NewParser(dataDir)
+	parser := newParser(dataDir)
 	currentCtx := ctxManager.Get()
 
 	// Get all tickets
@@ -111,7 +111,7 @@ func renderTaskCentricView(allTickets []types.Ticket, currentCtx *types.Context,
 
 	// Build a simple tree with the focused task and its subtasks
 	isTaskFocused := currentCtx.TaskKey == focusedTask.Key && currentCtx.SubtaskKey == ""
-	taskTitle := formatNodeTitle("Task", parser.RemoveJiraKey(focusedTask.Title), focusedTask.Key, isTaskFocused, taskStyle)
+	taskTitle := blockedMarker(*focusedTask) + formatNodeTitle("Task", parser.RemoveJiraKey(focusedTask.Title), focusedTask.Key, isTaskFocused, taskStyle)
 
 	// Add orphan indicator if no epic
 	if focusedTask.EpicKey == "" {
@@ -125,7 +125,7 @@ func renderTaskCentricView(allTickets []types.Ticket, currentCtx *types.Context,
 	subtasks := findChildSubtasks(focusedTask.Key, allTickets)
 	for _, subtask := range subtasks {
 		isSubtaskFocused := currentCtx.SubtaskKey == subtask.Key
-		subtaskTitle := formatNodeTitle("Subtask", parser.RemoveJiraKey(subtask.Title), subtask.Key, isSubtaskFocused, subtaskStyle)
+		subtaskTitle := blockedMarker(*subtask) + formatNodeTitle("Subtask", parser.RemoveJiraKey(subtask.Title), subtask.Key, isSubtaskFocused, subtaskStyle)
 		taskTree.Child(subtaskTitle)
 	}
 
@@ -165,18 +165,18 @@ func buildTree(epic *types.Ticket, tasks []*types.Ticket, allTickets []types.Tic
 		focusLevel = "epic"
 	}
 
-	epictitle := formatNodeTitle("Epic", parser.RemoveJiraKey(epic.Title), epic.Key, focusLevel == "epic" && ctx.EpicKey == epic.Key, epicStyle)
+	epictitle := blockedMarker(*epic) + formatNodeTitle("Epic", parser.RemoveJiraKey(epic.Title), epic.Key, focusLevel == "epic" && ctx.EpicKey == epic.Key, epicStyle)
 	tree := treepkg.New().Root(epictitle)
 
 	for _, task := range tasks {
 		isTaskFocused := focusLevel == "task" && ctx.TaskKey == task.Key
-		taskTitle := formatNodeTitle("Task", parser.RemoveJiraKey(task.Title), task.Key, isTaskFocused, taskStyle)
+		taskTitle := blockedMarker(*task) + formatNodeTitle("Task", parser.RemoveJiraKey(task.Title), task.Key, isTaskFocused, taskStyle)
 		taskTree := treepkg.New().Root(taskTitle)
 
 		subtasks := findChildSubtasks(task.Key, allTickets)
 		for _, subtask := range subtasks {
 			isSubFocused := focusLevel == "subtask" && ctx.SubtaskKey == subtask.Key
-			subtaskTitle := formatNodeTitle("Subtask", parser.RemoveJiraKey(subtask.Title), subtask.Key, isSubFocused, subtaskStyle)
+			subtaskTitle := blockedMarker(*subtask) + formatNodeTitle("Subtask", parser.RemoveJiraKey(subtask.Title), subtask.Key, isSubFocused, subtaskStyle)
 			taskTree.Child(subtaskTitle)
 		}
 		tree.Child(taskTree)
@@ -24,13 +24,15 @@ then enriches the content with AI, and optionally creates a Jira ticket.
 Examples:
   jai subtask                    # Create new subtask under current task
   jai subtask --no-enrich        # Skip AI enrichment
-  jai subtask --no-create        # Skip Jira ticket creation`,
+  jai subtask --no-create        # Skip Jira ticket creation
+  jai subtask --sprint active    # Move the parent task into the active sprint`,
 	RunE: runSubtask,
 }
 
 func init() {
 	subtaskCmd.Flags().BoolVar(&noEnrich, "no-enrich", false, "Skip AI enrichment")
 	subtaskCmd.Flags().BoolVar(&noCreate, "no-create", false, "Skip Jira ticket creation")
+	subtaskCmd.Flags().StringVar(&sprintQuery, "sprint", "", "Add the parent task to a sprint: active, next or a sprint name")
 	rootCmd.AddCommand(subtaskCmd)
 }
 
@@ -62,7 +64,7 @@ func runSubtask(cmd *cobra.Command, args []string) error {
 	epicKey := currentCtx.EpicKey // Optional, may be empty
 
 	// Initialize parser
-	parser := markdown.NewParser(dataDir)
+	parser := newParser(dataDir)
 
 	// Open editor for subtask drafting
 	rawContent, err := openEditorForSubtask()
@@ -84,9 +86,12 @@ func runSubtask(cmd *cobra.Command, args []string) error {
 		ParentKey:  taskKey,
 		Created:    time.Now(),
 		Updated:    time.Now(),
-		Assignee:   viper.GetString("jira.username"),
 	}
 
+	// Subtasks live in the same profile and project as their parent task
+	inheritProfile(dataDir, subtask, taskKey)
+	subtask.Assignee = profileUsername(subtask.Profile)
+
 	// Enrich with AI if enabled
 	if !noEnrich {
 		fmt.Println("Enriching subtask with AI...")
@@ -113,6 +118,13 @@ func runSubtask(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Check Jira will accept the subtask before writing anything locally
+	if !noCreate {
+		if err := preflightCreate(subtask); err != nil {
+			return err
+		}
+	}
+
 	// Create separate subtask file instead of adding to existing file
 	subtaskFilePath := parser.GetTaskFilePath("") // Will be renamed after Jira creation
 	if err := createSubtaskFile(parser, subtaskFilePath, subtask); err != nil {
@@ -123,13 +135,19 @@ func runSubtask(cmd *cobra.Command, args []string) error {
 
 	// Create Jira ticket if enabled
 	if !noCreate {
-		fmt.Println("Creating Jira ticket...")
-		if err := createJiraTicket(subtask); err != nil {
-			fmt.Printf("Warning: Failed to create Jira ticket: %v\n", err)
+		fmt.Printf("Creating %s ticket...\n", trackerName())
+		if err := createTrackerTicket(subtask); err != nil {
+			fmt.Printf("Warning: Failed to create %s ticket: %s\n", trackerName(), describeError(err))
+			// Keep the subtask with a placeholder key until 'jai push' can create it
+			if err := queueCreate(subtask, err); err != nil {
+				fmt.Printf("Warning: Failed to queue the subtask for 'jai push': %v\n", err)
+			}
 		} else {
-			fmt.Printf("Jira ticket created: %s\n", subtask.Key)
+			fmt.Printf("%s ticket created: %s\n", trackerName(), subtask.Key)
+		}
 
-			// Update the subtask file with the real Jira key and rename if needed
+		// Update the subtask file with the Jira (or placeholder) key and rename if needed
+		if subtask.Key != "" {
 			if err := updateSubtaskWithJiraKey(parser, subtaskFilePath, subtask); err != nil {
 				fmt.Printf("Warning: Failed to update subtask with Jira key: %v\n", err)
 			}
@@ -174,23 +192,6 @@ func runSubtask(cmd *cobra.Command, args []string) error {
 
 // openEditorForSubtask opens an editor for drafting a subtask
 func openEditorForSubtask() (string, error) {
-	// Get editor from config or environment
-	editor := viper.GetString("general.default_editor")
-	if editor == "" {
-		editor = os.Getenv("EDITOR")
-		if editor == "" {
-			editor = "vim" // Default fallback
-		}
-	}
-
-	// Create temporary file
-	tmpFile, err := os.CreateTemp("", "jai-subtask-*.md")
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %w", err)
-	}
-	defer os.Remove(tmpFile.Name())
-
-	// Write template to temp file
 	template := `## Overview
 Brief description of what this sub-task aims to achieve.
 
@@ -201,28 +202,8 @@ Brief description of what this sub-task aims to achieve.
 ## Notes
 Any additional notes or context...
 `
-	if _, err := tmpFile.WriteString(template); err != nil {
-		return "", fmt.Errorf("failed to write template: %w", err)
-	}
-	tmpFile.Close()
 
-	// Open editor
-	cmd := exec.Command(editor, tmpFile.Name())
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to run editor: %w", err)
-	}
-
-	// Read content back
-	content, err := os.ReadFile(tmpFile.Name())
-	if err != nil {
-		return "", fmt.Errorf("failed to read temp file: %w", err)
-	}
-
-	return string(content), nil
+	return openEditorWithTemplate("jai-subtask-*.md", template)
 }
 
 // createSubtaskFile creates a separate subtask file with task/epic references
@@ -234,13 +215,13 @@ func createSubtaskFile(parser *markdown.Parser, subtaskFilePath string, subtask
 	}
 
 	// Generate markdown content with task/epic references
-	content := generateSubtaskMarkdown(subtask)
+	content := generateSubtaskMarkdown(parser, subtask)
 
 	return os.WriteFile(subtaskFilePath, []byte(content), 0644)
 }
 
 // generateSubtaskMarkdown generates markdown content for a subtask with task/epic references
-func generateSubtaskMarkdown(subtask *types.Ticket) string {
+func generateSubtaskMarkdown(parser *markdown.Parser, subtask *types.Ticket) string {
 	var lines []string
 
 	// Add task reference at the top
@@ -278,21 +259,18 @@ func generateSubtaskMarkdown(subtask *types.Ticket) string {
 	}
 
 	// Add metadata section
-	lines = append(lines, "---")
-	lines = append(lines, "*Metadata:*")
-	if subtask.Key != "" {
-		lines = append(lines, fmt.Sprintf("- Key: %s", subtask.Key))
-	}
-	if subtask.Status != "" {
-		lines = append(lines, fmt.Sprintf("- Status: %s", subtask.Status))
-	}
-	if subtask.Priority != "" {
-		lines = append(lines, fmt.Sprintf("- Priority: %s", subtask.Priority))
+	lines = append(lines, parser.GenerateMetadata(*subtask)...)
+	lines = append(lines, "")
+
+	// Add issue links and comments pulled from Jira
+	if linkLines := parser.GenerateLinks(*subtask); len(linkLines) > 0 {
+		lines = append(lines, linkLines...)
+		lines = append(lines, "")
 	}
-	if subtask.ParentKey != "" {
-		lines = append(lines, fmt.Sprintf("- TaskKey: %s", subtask.ParentKey))
+	if commentLines := parser.GenerateComments(*subtask); len(commentLines) > 0 {
+		lines = append(lines, commentLines...)
+		lines = append(lines, "")
 	}
-	lines = append(lines, "")
 
 	return strings.Join(lines, "\n")
 }
@@ -309,6 +287,8 @@ func updateSubtaskWithJiraKey(parser *markdown.Parser, subtaskFilePath string, s
 	for i, s := range mdFile.Tickets {
 		if s.Type == types.TicketTypeSubtask && s.ParentKey == subtask.ParentKey && s.Title == subtask.Title {
 			mdFile.Tickets[i].Key = subtask.Key
+			mdFile.Tickets[i].Attachments = subtask.Attachments
+			mdFile.Tickets[i].Sprint = subtask.Sprint
 			// Update the subtask reference for regeneration
 			*subtask = mdFile.Tickets[i]
 			break
@@ -316,7 +296,7 @@ func updateSubtaskWithJiraKey(parser *markdown.Parser, subtaskFilePath string, s
 	}
 
 	// Regenerate the markdown content with the new key
-	content := generateSubtaskMarkdown(subtask)
+	content := generateSubtaskMarkdown(parser, subtask)
 
 	// Write the updated content back to the file
 	if err := os.WriteFile(subtaskFilePath, []byte(content), 0644); err != nil {
@@ -333,30 +313,13 @@ func updateSubtaskWithJiraKey(parser *markdown.Parser, subtaskFilePath string, s
 
 // renameSubtaskFile renames the subtask file to the correct SRE-####-{ticket title} format
 func renameSubtaskFile(currentPath string, subtask *types.Ticket) error {
-	// Create the new filename in the correct format
-	// Convert title to filename-safe format
-	safeTitle := strings.ReplaceAll(subtask.Title, " ", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "/", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "\\", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, ":", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "*", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "?", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "\"", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "<", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, ">", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "|", "-")
-
-	// Remove any double dashes and trim
-	safeTitle = strings.ReplaceAll(safeTitle, "--", "-")
-	safeTitle = strings.Trim(safeTitle, "-")
-
 	// Use subtask key if available, otherwise generate one
 	subtaskKey := subtask.Key
 	if subtaskKey == "" {
 		subtaskKey = generateSubtaskKey(subtask.Title)
 	}
 
-	newFilename := fmt.Sprintf("%s-%s.md", subtaskKey, safeTitle)
+	newFilename := ticketFileName(subtaskKey, subtask.Title)
 
 	// Get the directory of the current file
 	dir := filepath.Dir(currentPath)
@@ -0,0 +1,537 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lunchboxsushi/jai/internal/convert"
+	"github.com/lunchboxsushi/jai/internal/jira"
+	"github.com/lunchboxsushi/jai/internal/markdown"
+	"github.com/lunchboxsushi/jai/internal/merge"
+	"github.com/lunchboxsushi/jai/internal/outbox"
+	"github.com/lunchboxsushi/jai/internal/snapshot"
+	"github.com/lunchboxsushi/jai/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync local markdown tickets with Jira",
+	Long: `Sync every ticket under the tickets directory with Jira. Local edits to the title,
+description and priority are pushed to Jira, the remote status and priority are
+pulled back into each ticket's metadata section, and Jira comments are written to
+each ticket's comments section.
+
+Descriptions are merged three-way against a snapshot taken at the last sync (stored
+under snapshots/ in the data directory): local and Jira edits that don't overlap are
+combined and pushed, and overlapping edits are written into the markdown file between
+conflict markers (<<<<<<< local / ======= / >>>>>>> remote). Resolve them and sync again.
+
+Tickets without a snapshot yet, and titles, follow the modification times: local
+content is only pushed when the markdown file was edited after the last change in
+Jira. Otherwise the remote side wins: the remote title is written into the markdown
+file, and local description differences are reported. The description is only sent
+to Jira when it is being pushed.
+
+Local files linked from a description by path (e.g. ![](./img/trace.png)) are
+uploaded as Jira attachments, and the copy of the description in Jira links to the
+attachment instead. Uploaded files are recorded in the Attachments metadata line so
+they are only uploaded once.
+
+Pushes that fail are queued in the outbox and retried by 'jai push'. Tickets with a
+placeholder key (e.g. PENDING-3) aren't in Jira yet and are skipped until pushed.
+
+Each ticket is synced with the Jira instance of the profile it was created under.
+With --profile, only that profile's tickets are synced.
+
+Examples:
+  jai sync                    # Push local edits and pull remote status
+  jai sync --dry-run          # Show what would change without writing anything
+  jai sync --diff             # Show field-level differences for each ticket
+  jai sync --status-only      # Only pull status and priority from Jira
+  jai sync --force            # Push local edits even if Jira changed more recently`,
+	RunE: runSync,
+}
+
+var syncOpts types.SyncOptions
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncOpts.DryRun, "dry-run", false, "Show what would change without writing to Jira or local files")
+	syncCmd.Flags().BoolVar(&syncOpts.Diff, "diff", false, "Show field-level differences between local and remote tickets")
+	syncCmd.Flags().BoolVar(&syncOpts.Status, "status-only", false, "Only pull status and priority from Jira")
+	syncCmd.Flags().BoolVar(&syncOpts.Force, "force", false, "Push local edits even if Jira was updated more recently")
+	rootCmd.AddCommand(syncCmd)
+}
+
+// syncResult summarizes what happened to a single ticket during sync
+type syncResult struct {
+	pushed    []string
+	pulled    []string
+	skipped   []string
+	conflicts []string
+	hasDiffs  bool
+
+	// Description to write into the markdown file after a pull or merge
+	writeBody bool
+	body      string
+
+	// Description both sides agree on after the sync, recorded as the next merge base
+	saveBase bool
+	base     string
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	if err := requireJira("sync"); err != nil {
+		return err
+	}
+
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
+	}
+
+	parser := newParser(dataDir)
+	mdFiles, err := findTicketFiles(dataDir, parser)
+	if err != nil {
+		return fmt.Errorf("failed to find tickets: %w", err)
+	}
+
+	// Tickets are synced with the Jira instance and project they belong to
+	clients := newProfileClients()
+
+	if syncOpts.DryRun {
+		fmt.Println("Dry run: no changes will be written")
+	}
+
+	snapshots := snapshot.NewStore(dataDir)
+
+	// Pushes that fail are queued for 'jai push'
+	ob := outbox.New(dataDir)
+	if err := ob.Load(); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	var pushed, pulled, unchanged, conflicts, failed int
+	for _, mdFile := range mdFiles {
+		info, err := os.Stat(mdFile.Path)
+		if err != nil {
+			fmt.Printf("Warning: Failed to stat %s: %v\n", mdFile.Path, err)
+			continue
+		}
+
+		for _, local := range mdFile.Tickets {
+			if local.Key == "" || !inProfile(local) {
+				continue
+			}
+			if outbox.IsPlaceholder(local.Key) {
+				fmt.Printf("… %s: not in Jira yet, run 'jai push' to create it\n", local.Key)
+				continue
+			}
+
+			jiraClient, err := clients.get(&local)
+			if err != nil {
+				fmt.Printf("✗ %s: %s\n", local.Key, describeError(err))
+				failed++
+				continue
+			}
+
+			remote, err := jiraClient.GetTicket(local.Key)
+			if err != nil {
+				fmt.Printf("✗ %s: %s\n", local.Key, describeError(err))
+				failed++
+				continue
+			}
+
+			// Attach newly linked local files, and read links to attachments in Jira's copy
+			// of the description as the local paths they were uploaded from
+			uploaded := 0
+			if !syncOpts.DryRun && !syncOpts.Status {
+				uploaded = uploadAttachments(jiraClient, &local, filepath.Dir(mdFile.Path))
+			}
+			remote.Description = jira.LocalAttachmentLinks(remote.Description, local.Attachments)
+
+			base, err := snapshots.Get(local.Key)
+			if err != nil {
+				fmt.Printf("Warning: %v\n", err)
+			}
+
+			// Without a merge base, local edits win only if the file was touched after the last remote change
+			localNewer := info.ModTime().After(remote.Updated)
+			updated, result := mergeSyncTicket(parser, local, remote, base, localNewer)
+			if uploaded > 0 && !contains(result.pushed, "description") {
+				// Jira's description still has the local paths, so push it with the attachment links
+				result.pushed = append(result.pushed, "attachments")
+			}
+
+			if len(result.pushed) == 0 && len(result.pulled) == 0 && len(result.conflicts) == 0 {
+				unchanged++
+			}
+			if len(result.conflicts) > 0 {
+				conflicts++
+			}
+			printSyncResult(local.Key, mdFile.Path, result)
+			if syncOpts.Diff && result.hasDiffs {
+				printSyncDiff(parser, local, remote)
+			}
+
+			if syncOpts.DryRun {
+				if len(result.pushed) > 0 {
+					pushed++
+				}
+				if len(result.pulled) > 0 {
+					pulled++
+				}
+				continue
+			}
+
+			if len(result.pushed) > 0 {
+				// Resending an untouched description would rewrite it in Jira after a lossy
+				// round trip through markdown, so it only goes out when it is being pushed
+				push := *updated
+				if !contains(result.pushed, "description") && !contains(result.pushed, "attachments") {
+					push.Description = ""
+				}
+				if err := jiraClient.UpdateTicket(&push); err != nil {
+					fmt.Printf("✗ %s: failed to push changes: %s (queued for 'jai push')\n", local.Key, describeError(err))
+					ob.Add(outbox.KindUpdate, local.Key, local.Type, describeError(err))
+					failed++
+					continue
+				}
+				if op := ob.Find(outbox.KindUpdate, local.Key); op != nil {
+					ob.Remove(op)
+				}
+				pushed++
+			}
+
+			if result.writeBody {
+				if err := parser.UpdateDescription(mdFile.Path, local.Key, result.body); err != nil {
+					fmt.Printf("✗ %s: failed to update %s: %v\n", local.Key, mdFile.Path, err)
+					failed++
+					continue
+				}
+			}
+
+			if contains(result.pulled, "title") {
+				if err := parser.UpdateTitle(mdFile.Path, local.Key, updated.Title); err != nil {
+					fmt.Printf("✗ %s: failed to update %s: %v\n", local.Key, mdFile.Path, err)
+					failed++
+					continue
+				}
+			}
+
+			if len(result.pulled) > 0 || uploaded > 0 {
+				if err := parser.UpdateTicket(mdFile.Path, *updated); err != nil {
+					fmt.Printf("✗ %s: failed to update %s: %v\n", local.Key, mdFile.Path, err)
+					failed++
+					continue
+				}
+				if len(result.pulled) > 0 {
+					pulled++
+				}
+			}
+
+			if result.saveBase {
+				if err := snapshots.Put(local.Key, result.base); err != nil {
+					fmt.Printf("Warning: %v\n", err)
+				}
+			}
+		}
+	}
+
+	if !syncOpts.DryRun {
+		if err := ob.Save(); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Sync complete: %d pushed, %d pulled, %d unchanged, %d conflicts, %d failed\n", pushed, pulled, unchanged, conflicts, failed)
+	if conflicts > 0 {
+		fmt.Println("Resolve the conflict markers in the affected files, then run 'jai sync' again")
+	}
+	return nil
+}
+
+// mergeSyncTicket decides which side wins for each synced field and returns the merged ticket
+func mergeSyncTicket(parser *markdown.Parser, local types.Ticket, remote *types.Ticket, base *snapshot.Snapshot, localNewer bool) (*types.Ticket, syncResult) {
+	var result syncResult
+	merged := local
+	merged.Title = parser.RemoveJiraKey(local.Title)
+
+	canPush := !syncOpts.Status && (localNewer || syncOpts.Force)
+
+	// Titles follow whichever side changed last, and are left alone by --status-only
+	if !syncOpts.Status && merged.Title != strings.TrimSpace(remote.Title) {
+		result.hasDiffs = true
+		if canPush {
+			result.pushed = append(result.pushed, "title")
+		} else {
+			merged.Title = strings.TrimSpace(remote.Title)
+			result.pulled = append(result.pulled, "title")
+		}
+	}
+
+	merged.Description = remote.Description
+	if !syncOpts.Status {
+		mergeSyncDescription(&merged, local, remote, base, canPush, &result)
+	}
+
+	// Priority can move either way depending on which side changed last
+	if local.Priority != remote.Priority && remote.Priority != "" {
+		result.hasDiffs = true
+		if local.Priority != "" && canPush {
+			result.pushed = append(result.pushed, "priority")
+		} else {
+			merged.Priority = remote.Priority
+			result.pulled = append(result.pulled, "priority")
+		}
+	}
+
+	// Custom fields, like priority, move either way depending on which side changed last
+	if !syncOpts.Status {
+		config, err := loadTicketConfig(&local)
+		if err != nil {
+			config = loadJiraConfig()
+		}
+		customFields, pushed, pulled := mergeCustomFields(config, local.CustomFields, remote.CustomFields, canPush)
+		merged.CustomFields = customFields
+		if pushed {
+			result.hasDiffs = true
+			result.pushed = append(result.pushed, "custom fields")
+		}
+		if pulled {
+			result.hasDiffs = true
+			result.pulled = append(result.pulled, "custom fields")
+		}
+	}
+
+	// Status is owned by Jira
+	if local.Status != remote.Status && remote.Status != "" {
+		result.hasDiffs = true
+		merged.Status = remote.Status
+		result.pulled = append(result.pulled, "status")
+	}
+
+	// Sprint planning happens on the board in Jira
+	if !syncOpts.Status && local.Sprint != remote.Sprint && remote.Sprint != "" {
+		merged.Sprint = remote.Sprint
+		result.pulled = append(result.pulled, "sprint")
+	}
+
+	// Comments are posted with 'jai comment', so the local section mirrors Jira
+	if !syncOpts.Status && !commentsEqual(local.Comments, remote.Comments) {
+		merged.Comments = remote.Comments
+		result.pulled = append(result.pulled, "comments")
+	}
+
+	// Links are created with 'jai link' and carry the linked tickets' statuses
+	if !syncOpts.Status && !linksEqual(local.Links, remote.Links) {
+		merged.Links = remote.Links
+		result.pulled = append(result.pulled, "links")
+	}
+
+	return &merged, result
+}
+
+// mergeSyncDescription reconciles the local and remote descriptions. With a snapshot of the
+// last sync, each side's changes are merged three-way and overlapping edits are written to
+// the markdown file between conflict markers. Without one, the most recently edited side wins.
+func mergeSyncDescription(merged *types.Ticket, local types.Ticket, remote *types.Ticket, base *snapshot.Snapshot, canPush bool, result *syncResult) {
+	localDesc := strings.TrimSpace(local.Description)
+	remoteDesc := strings.TrimSpace(remote.Description)
+
+	if merge.HasConflicts(localDesc) {
+		// Nothing is pushed until the markers from an earlier sync are resolved
+		result.hasDiffs = true
+		result.conflicts = append(result.conflicts, "description")
+		return
+	}
+
+	if sameMarkdown(localDesc, remoteDesc) {
+		result.saveBase, result.base = true, remoteDesc
+		return
+	}
+	result.hasDiffs = true
+
+	if base == nil {
+		switch {
+		case localDesc == "":
+			// Nothing written locally yet
+		case canPush:
+			merged.Description = localDesc
+			result.pushed = append(result.pushed, "description")
+			result.saveBase, result.base = true, localDesc
+		default:
+			result.skipped = append(result.skipped, "description")
+		}
+		return
+	}
+
+	localChanged := !sameMarkdown(localDesc, base.Description)
+	remoteChanged := !sameMarkdown(remoteDesc, base.Description)
+
+	switch {
+	case localChanged && !remoteChanged:
+		merged.Description = localDesc
+		result.pushed = append(result.pushed, "description")
+		result.saveBase, result.base = true, localDesc
+
+	case remoteChanged && !localChanged:
+		result.pulled = append(result.pulled, "description")
+		result.writeBody, result.body = true, remoteDesc
+		result.saveBase, result.base = true, remoteDesc
+
+	default:
+		m := merge.Merge(splitLines(base.Description), splitLines(localDesc), splitLines(remoteDesc))
+		mergedDesc := strings.Join(m.Lines, "\n")
+		result.writeBody, result.body = true, mergedDesc
+
+		if m.Conflicts > 0 {
+			// Jira keeps its version; resolving the markers locally produces the next push
+			result.conflicts = append(result.conflicts, "description")
+			result.saveBase, result.base = true, remoteDesc
+			return
+		}
+		merged.Description = mergedDesc
+		result.pushed = append(result.pushed, "description")
+		result.pulled = append(result.pulled, "description")
+		result.saveBase, result.base = true, mergedDesc
+	}
+}
+
+// splitLines splits text into lines for merging
+func splitLines(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// printSyncResult prints a one-line summary of the sync outcome for a ticket
+func printSyncResult(key, path string, result syncResult) {
+	if len(result.conflicts) > 0 {
+		fmt.Printf("⚠ %s: conflicting edits to %s, see the markers in %s\n", key, strings.Join(result.conflicts, ", "), path)
+	}
+	if len(result.pushed) == 0 && len(result.pulled) == 0 && len(result.skipped) == 0 {
+		if verbose {
+			fmt.Printf("= %s: up to date\n", key)
+		}
+		return
+	}
+
+	var parts []string
+	if len(result.pushed) > 0 {
+		parts = append(parts, "pushed "+strings.Join(result.pushed, ", "))
+	}
+	if len(result.pulled) > 0 {
+		parts = append(parts, "pulled "+strings.Join(result.pulled, ", "))
+	}
+	if len(result.skipped) > 0 && !syncOpts.Status {
+		parts = append(parts, "kept remote "+strings.Join(result.skipped, ", ")+" (Jira changed more recently, use --force to overwrite)")
+	}
+	fmt.Printf("↻ %s: %s\n", key, strings.Join(parts, "; "))
+}
+
+// printSyncDiff prints the field-level differences between a local and remote ticket
+func printSyncDiff(parser *markdown.Parser, local types.Ticket, remote *types.Ticket) {
+	printFieldDiff("title", remote.Title, parser.RemoveJiraKey(local.Title))
+	if strings.TrimSpace(local.Description) != "" {
+		printFieldDiff("description", remote.Description, local.Description)
+	}
+	printFieldDiff("priority", remote.Priority, local.Priority)
+	printFieldDiff("status", remote.Status, local.Status)
+}
+
+// printFieldDiff prints a line diff for a single field, prefixed with - for Jira and + for local
+func printFieldDiff(field, remote, local string) {
+	remote = strings.TrimSpace(remote)
+	local = strings.TrimSpace(local)
+	if remote == local {
+		return
+	}
+
+	fmt.Printf("    %s:\n", field)
+	for _, line := range merge.Diff(strings.Split(remote, "\n"), strings.Split(local, "\n")) {
+		fmt.Printf("      %s\n", line)
+	}
+}
+
+// commentsEqual reports whether two comment lists render the same in markdown
+func commentsEqual(a, b []types.Comment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Author != b[i].Author || !sameMarkdown(a[i].Body, b[i].Body) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeCustomFields reconciles the custom fields mapped in jira.custom_fields. Fields only
+// set in Jira are pulled; fields set on both sides with different values are pushed when
+// local edits win and pulled otherwise. Unmapped metadata lines are kept as they are.
+func mergeCustomFields(config *types.Config, local, remote map[string]interface{}, canPush bool) (merged map[string]interface{}, pushed, pulled bool) {
+	merged = make(map[string]interface{}, len(local))
+	localByID := make(map[string]string)
+	for name, value := range local {
+		merged[name] = value
+		if id := jira.CustomFieldID(config, name); id != "" {
+			localByID[id] = name
+		}
+	}
+
+	for name, value := range remote {
+		id := jira.CustomFieldID(config, name)
+		localName, ok := localByID[id]
+		switch {
+		case !ok:
+			merged[name] = value
+			pulled = true
+		case fmt.Sprint(local[localName]) == fmt.Sprint(value):
+		case canPush && fmt.Sprint(local[localName]) != "":
+			pushed = true
+		default:
+			merged[localName] = value
+			pulled = true
+		}
+	}
+
+	if len(merged) == 0 {
+		return nil, pushed, pulled
+	}
+	return merged, pushed, pulled
+}
+
+// linksEqual reports whether two lists of issue links match, including linked statuses
+func linksEqual(a, b []types.Link) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameMarkdown reports whether two markdown texts render to the same Jira markup, so
+// formatting that doesn't survive the round trip through Jira (e.g. _em_ vs *em*) is ignored
+func sameMarkdown(a, b string) bool {
+	return convert.MarkdownToWiki(strings.TrimSpace(a)) == convert.MarkdownToWiki(strings.TrimSpace(b))
+}
+
+// contains reports whether s is present in list
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -27,7 +27,8 @@ Examples:
   jai task                    # Create new task under current epic
   jai task --orphan           # Create parentless task (no epic)
   jai task --no-enrich        # Skip AI enrichment
-  jai task --no-create        # Skip Jira ticket creation`,
+  jai task --no-create        # Skip Jira ticket creation
+  jai task --sprint next      # Add the task to the next sprint`,
 	RunE: runTask,
 }
 
@@ -41,6 +42,8 @@ func init() {
 	taskCmd.Flags().BoolVar(&noEnrich, "no-enrich", false, "Skip AI enrichment")
 	taskCmd.Flags().BoolVar(&noCreate, "no-create", false, "Skip Jira ticket creation")
 	taskCmd.Flags().BoolVarP(&orphan, "orphan", "o", false, "Create task without parent epic")
+	taskCmd.Flags().StringVar(&sprintQuery, "sprint", "", "Add the new ticket to a sprint: active, next or a sprint name")
+	taskCmd.Flags().StringVar(&projectOverride, "project", "", "Create the task in this Jira project instead of its epic's or the configured one")
 	rootCmd.AddCommand(taskCmd)
 }
 
@@ -79,7 +82,7 @@ func runTask(cmd *cobra.Command, args []string) error {
 	}
 
 	// Initialize parser
-	parser := markdown.NewParser(dataDir)
+	parser := newParser(dataDir)
 
 	// Open editor for task drafting
 	rawContent, err := openEditorForTask()
@@ -100,9 +103,12 @@ func runTask(cmd *cobra.Command, args []string) error {
 		EpicKey:    epicKey, // Will be empty for orphan tasks
 		Created:    time.Now(),
 		Updated:    time.Now(),
-		Assignee:   viper.GetString("jira.username"),
 	}
 
+	// Tasks live in the same profile and project as their epic
+	inheritProfile(dataDir, task, epicKey)
+	task.Assignee = profileUsername(task.Profile)
+
 	// Enrich with AI if enabled
 	if !noEnrich {
 		fmt.Println("Enriching task with AI...")
@@ -129,6 +135,13 @@ func runTask(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Check Jira will accept the task before writing anything locally
+	if !noCreate {
+		if err := preflightCreate(task); err != nil {
+			return err
+		}
+	}
+
 	// Create separate task file instead of adding to epic
 	taskFilePath := parser.GetTaskFilePath("") // Will be renamed after Jira creation
 	if err := createTaskFile(parser, taskFilePath, task); err != nil {
@@ -139,13 +152,19 @@ func runTask(cmd *cobra.Command, args []string) error {
 
 	// Create Jira ticket if enabled
 	if !noCreate {
-		fmt.Println("Creating Jira ticket...")
-		if err := createJiraTicket(task); err != nil {
-			fmt.Printf("Warning: Failed to create Jira ticket: %v\n", err)
+		fmt.Printf("Creating %s ticket...\n", trackerName())
+		if err := createTrackerTicket(task); err != nil {
+			fmt.Printf("Warning: Failed to create %s ticket: %s\n", trackerName(), describeError(err))
+			// Keep the task with a placeholder key until 'jai push' can create it
+			if err := queueCreate(task, err); err != nil {
+				fmt.Printf("Warning: Failed to queue the task for 'jai push': %v\n", err)
+			}
 		} else {
-			fmt.Printf("Jira ticket created: %s\n", task.Key)
+			fmt.Printf("%s ticket created: %s\n", trackerName(), task.Key)
+		}
 
-			// Update the task file with the real Jira key and rename if needed
+		// Update the task file with the Jira (or placeholder) key and rename if needed
+		if task.Key != "" {
 			if err := updateTaskWithJiraKey(parser, taskFilePath, task); err != nil {
 				fmt.Printf("Warning: Failed to update task with Jira key: %v\n", err)
 			}
@@ -198,23 +217,6 @@ func runTask(cmd *cobra.Command, args []string) error {
 
 // openEditorForTask opens an editor for drafting a task
 func openEditorForTask() (string, error) {
-	// Get editor from config or environment
-	editor := viper.GetString("general.default_editor")
-	if editor == "" {
-		editor = os.Getenv("EDITOR")
-		if editor == "" {
-			editor = "vim" // Default fallback
-		}
-	}
-
-	// Create temporary file
-	tmpFile, err := os.CreateTemp("", "jai-task-*.md")
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %w", err)
-	}
-	defer os.Remove(tmpFile.Name())
-
-	// Write template to temp file
 	template := `## Overview
 Brief description of what this task aims to achieve.
 
@@ -225,28 +227,8 @@ Brief description of what this task aims to achieve.
 ## Notes
 Any additional notes or context...
 `
-	if _, err := tmpFile.WriteString(template); err != nil {
-		return "", fmt.Errorf("failed to write template: %w", err)
-	}
-	tmpFile.Close()
 
-	// Open editor
-	cmd := exec.Command(editor, tmpFile.Name())
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to run editor: %w", err)
-	}
-
-	// Read content back
-	content, err := os.ReadFile(tmpFile.Name())
-	if err != nil {
-		return "", fmt.Errorf("failed to read temp file: %w", err)
-	}
-
-	return string(content), nil
+	return openEditorWithTemplate("jai-task-*.md", template)
 }
 
 // extractTitleFromContent extracts a title from the raw content
@@ -339,13 +321,13 @@ func createTaskFile(parser *markdown.Parser, taskFilePath string, task *types.Ti
 	}
 
 	// Generate markdown content with epic reference
-	content := generateTaskMarkdown(task)
+	content := generateTaskMarkdown(parser, task)
 
 	return os.WriteFile(taskFilePath, []byte(content), 0644)
 }
 
 // generateTaskMarkdown generates markdown content for a task with epic reference
-func generateTaskMarkdown(task *types.Ticket) string {
+func generateTaskMarkdown(parser *markdown.Parser, task *types.Ticket) string {
 	var lines []string
 
 	// Add epic reference at the top only if task has an epic
@@ -377,63 +359,49 @@ func generateTaskMarkdown(task *types.Ticket) string {
 	}
 
 	// Add metadata section
-	lines = append(lines, "---")
-	lines = append(lines, "*Metadata:*")
-	if task.Key != "" {
-		lines = append(lines, fmt.Sprintf("- Key: %s", task.Key))
-	}
-	if task.Status != "" {
-		lines = append(lines, fmt.Sprintf("- Status: %s", task.Status))
-	}
-	if task.Priority != "" {
-		lines = append(lines, fmt.Sprintf("- Priority: %s", task.Priority))
+	lines = append(lines, parser.GenerateMetadata(*task)...)
+	lines = append(lines, "")
+
+	// Add issue links and comments pulled from Jira
+	if linkLines := parser.GenerateLinks(*task); len(linkLines) > 0 {
+		lines = append(lines, linkLines...)
+		lines = append(lines, "")
 	}
-	if task.EpicKey != "" {
-		lines = append(lines, fmt.Sprintf("- ParentKey: %s", task.EpicKey))
+	if commentLines := parser.GenerateComments(*task); len(commentLines) > 0 {
+		lines = append(lines, commentLines...)
+		lines = append(lines, "")
 	}
-	lines = append(lines, "")
 
 	return strings.Join(lines, "\n")
 }
 
-// createJiraTicket creates a Jira ticket for the task
-func createJiraTicket(task *types.Ticket) error {
-	// Get Jira config
-	jiraConfig := &types.Config{
-		Jira: struct {
-			URL           string `yaml:"url" json:"url"`
-			Username      string `yaml:"username" json:"username"`
-			Token         string `yaml:"token" json:"token"`
-			Project       string `yaml:"project" json:"project"`
-			EpicLinkField string `yaml:"epic_link_field" json:"epic_link_field"`
-		}{
-			URL:           viper.GetString("jira.url"),
-			Username:      viper.GetString("jira.username"),
-			Token:         os.Getenv("JAI_JIRA_TOKEN"),
-			Project:       viper.GetString("jira.project"),
-			EpicLinkField: viper.GetString("jira.epic_link_field"),
-		},
-	}
-
-	if jiraConfig.Jira.URL == "" || jiraConfig.Jira.Username == "" || jiraConfig.Jira.Token == "" {
-		return fmt.Errorf("Jira configuration incomplete (check URL, username, and JAI_JIRA_TOKEN environment variable)")
-	}
-
-	// Create Jira client using our internal wrapper
-	jiraClient, err := jira.NewClient(jiraConfig)
+// createTrackerTicket creates the task in the configured tracker
+func createTrackerTicket(task *types.Ticket) error {
+	// Create the backend for the task's profile and project
+	backend, err := newTicketBackend(task)
 	if err != nil {
-		return fmt.Errorf("failed to create Jira client: %w", err)
+		return err
 	}
 
 	// Create the ticket using our wrapper
-	createdTicket, err := jiraClient.CreateTicket(task)
+	createdTicket, err := backend.CreateTicket(task)
 	if err != nil {
-		return fmt.Errorf("failed to create Jira ticket: %w", err)
+		return fmt.Errorf("failed to create %s ticket: %w", trackerName(), err)
 	}
 
 	// Update the task with the created data
 	*task = *createdTicket
 
+	// Attachments and sprints only exist in Jira
+	if jiraClient, ok := backend.(*jira.Client); ok {
+		// Upload files linked from the description now that there is an issue to attach them to
+		attachAfterCreate(jiraClient, task)
+
+		if sprintQuery != "" {
+			addToSprint(jiraClient, task, sprintQuery)
+		}
+	}
+
 	return nil
 }
 
@@ -533,6 +501,8 @@ func updateTaskWithJiraKey(parser *markdown.Parser, taskFilePath string, task *t
 	for i, t := range mdFile.Tickets {
 		if t.Type == types.TicketTypeTask && t.EpicKey == task.EpicKey && t.Title == task.Title {
 			mdFile.Tickets[i].Key = task.Key
+			mdFile.Tickets[i].Attachments = task.Attachments
+			mdFile.Tickets[i].Sprint = task.Sprint
 			// Update the task reference for regeneration
 			*task = mdFile.Tickets[i]
 			break
@@ -540,7 +510,7 @@ func updateTaskWithJiraKey(parser *markdown.Parser, taskFilePath string, task *t
 	}
 
 	// Regenerate the markdown content with the new key
-	content := generateTaskMarkdown(task)
+	content := generateTaskMarkdown(parser, task)
 
 	// Write the updated content back to the file
 	if err := os.WriteFile(taskFilePath, []byte(content), 0644); err != nil {
@@ -557,30 +527,13 @@ func updateTaskWithJiraKey(parser *markdown.Parser, taskFilePath string, task *t
 
 // renameTaskFile renames the task file to the correct SRE-####-{ticket title} format
 func renameTaskFile(currentPath string, task *types.Ticket) error {
-	// Create the new filename in the correct format
-	// Convert title to filename-safe format
-	safeTitle := strings.ReplaceAll(task.Title, " ", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "/", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "\\", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, ":", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "*", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "?", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "\"", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "<", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, ">", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "|", "-")
-
-	// Remove any double dashes and trim
-	safeTitle = strings.ReplaceAll(safeTitle, "--", "-")
-	safeTitle = strings.Trim(safeTitle, "-")
-
 	// Use task key if available, otherwise generate one
 	taskKey := task.Key
 	if taskKey == "" {
 		taskKey = generateTaskKey(task.Title)
 	}
 
-	newFilename := fmt.Sprintf("%s-%s.md", taskKey, safeTitle)
+	newFilename := ticketFileName(taskKey, task.Title)
 
 	// Get the directory of the current file
 	dir := filepath.Dir(currentPath)
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lunchboxsushi/jai/internal/github"
+	"github.com/lunchboxsushi/jai/internal/gitlab"
+	"github.com/lunchboxsushi/jai/internal/linear"
+	"github.com/lunchboxsushi/jai/internal/tracker"
+	"github.com/lunchboxsushi/jai/internal/types"
+	"github.com/spf13/viper"
+)
+
+// trackerType returns the tracker picked with tracker.type, Jira unless configured otherwise
+func trackerType() string {
+	if t := strings.ToLower(strings.TrimSpace(viper.GetString("tracker.type"))); t != "" {
+		return t
+	}
+	return tracker.TypeJira
+}
+
+// trackerName returns the configured tracker's name for messages, e.g. "Jira"
+func trackerName() string {
+	switch trackerType() {
+	case tracker.TypeJira:
+		return "Jira"
+	case tracker.TypeLocal:
+		return "Local"
+	case tracker.TypeGitHub:
+		return "GitHub"
+	case tracker.TypeGitLab:
+		return "GitLab"
+	case tracker.TypeLinear:
+		return "Linear"
+	default:
+		return trackerType()
+	}
+}
+
+// usesJira reports whether tickets live in Jira
+func usesJira() bool {
+	return trackerType() == tracker.TypeJira
+}
+
+// requireJira refuses commands that only work against Jira when another tracker is configured
+func requireJira(command string) error {
+	if usesJira() {
+		return nil
+	}
+	return fmt.Errorf("'jai %s' only works with Jira, but tracker.type is %q", command, trackerType())
+}
+
+// newTicketBackend creates the tracker backend a ticket is created in. Jira tickets use the
+// client for their profile and project.
+func newTicketBackend(ticket *types.Ticket) (tracker.Backend, error) {
+	switch trackerType() {
+	case tracker.TypeJira:
+		jiraClient, err := newTicketClient(ticket)
+		if err != nil {
+			return nil, err
+		}
+		return jiraClient, nil
+	case tracker.TypeLocal:
+		config := loadTrackerConfig()
+		// Keys must look like PROJ-123 for the markdown headers to pick them up
+		prefix := config.Tracker.Local.Prefix
+		if strings.Trim(prefix, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+			return nil, fmt.Errorf("tracker.local.prefix must only contain letters, got %q", prefix)
+		}
+		return tracker.NewLocal(config.General.DataDir, prefix), nil
+	case tracker.TypeGitHub:
+		config := loadTrackerConfig()
+		if config.Tracker.GitHub.Token == "" {
+			return nil, fmt.Errorf("GitHub configuration incomplete: JAI_GITHUB_TOKEN not set")
+		}
+		githubClient, err := github.NewClient(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		return githubClient, nil
+	case tracker.TypeGitLab:
+		config := loadTrackerConfig()
+		if config.Tracker.GitLab.Token == "" {
+			return nil, fmt.Errorf("GitLab configuration incomplete: JAI_GITLAB_TOKEN not set")
+		}
+		gitlabClient, err := gitlab.NewClient(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GitLab client: %w", err)
+		}
+		return gitlabClient, nil
+	case tracker.TypeLinear:
+		config := loadTrackerConfig()
+		if config.Tracker.Linear.Token == "" {
+			return nil, fmt.Errorf("Linear configuration incomplete: JAI_LINEAR_TOKEN not set")
+		}
+		linearClient, err := linear.NewClient(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Linear client: %w", err)
+		}
+		return linearClient, nil
+	default:
+		return nil, fmt.Errorf("unknown tracker.type %q (use %s, %s, %s, %s or %s)", trackerType(),
+			tracker.TypeJira, tracker.TypeLocal, tracker.TypeGitHub, tracker.TypeGitLab, tracker.TypeLinear)
+	}
+}
+
+// loadTrackerConfig builds the settings of the trackers other than Jira from viper and the
+// environment
+func loadTrackerConfig() *types.Config {
+	config := &types.Config{}
+	config.Tracker.Type = trackerType()
+	config.Tracker.Local.Prefix = strings.ToUpper(viper.GetString("tracker.local.prefix"))
+	config.Tracker.GitHub.URL = viper.GetString("tracker.github.url")
+	config.Tracker.GitHub.Repo = viper.GetString("tracker.github.repo")
+	config.Tracker.GitHub.Token = os.Getenv("JAI_GITHUB_TOKEN")
+	config.Tracker.GitLab.URL = viper.GetString("tracker.gitlab.url")
+	config.Tracker.GitLab.Project = viper.GetString("tracker.gitlab.project")
+	config.Tracker.GitLab.Group = viper.GetString("tracker.gitlab.group")
+	config.Tracker.GitLab.Token = os.Getenv("JAI_GITLAB_TOKEN")
+	config.Tracker.Linear.URL = viper.GetString("tracker.linear.url")
+	config.Tracker.Linear.Team = viper.GetString("tracker.linear.team")
+	config.Tracker.Linear.Token = os.Getenv("JAI_LINEAR_TOKEN")
+	config.General.DataDir, _ = getDataDir()
+	return config
+}
+
+// newKeyBackend creates the tracker backend for an existing ticket, using the local copy
+// to pick the Jira profile and project if there is one
+func newKeyBackend(key string) (tracker.Backend, error) {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return nil, err
+	}
+	ticket := &types.Ticket{Key: key, Profile: activeProfile()}
+	if _, local, err := findTicketByKey(dataDir, newParser(dataDir), key); err == nil {
+		ticket = local
+	}
+	return newTicketBackend(ticket)
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lunchboxsushi/jai/internal/types"
+	"github.com/spf13/viper"
+)
+
+// useLocalTracker points the config at a temporary data directory with tracker.type local
+func useLocalTracker(t *testing.T, prefix string) string {
+	t.Helper()
+	dataDir := t.TempDir()
+	viper.Set("general.data_dir", dataDir)
+	viper.Set("tracker.type", "local")
+	viper.Set("tracker.local.prefix", prefix)
+	t.Cleanup(viper.Reset)
+	return dataDir
+}
+
+// createLocalTask creates a task through the configured tracker and writes its file the way
+// 'jai task' does
+func createLocalTask(t *testing.T, dataDir, title string) *types.Ticket {
+	t.Helper()
+	task := &types.Ticket{Type: types.TicketTypeTask, Title: title, EpicKey: "OBS-1"}
+	if err := createTrackerTicket(task); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dataDir, "tickets", ticketFileName(task.Key, task.Title))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(generateTaskMarkdown(newParser(dataDir), task)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return task
+}
+
+func TestCreateTrackerTicketLocal(t *testing.T) {
+	dataDir := useLocalTracker(t, "obs")
+
+	first := createLocalTask(t, dataDir, "Add spans")
+	second := createLocalTask(t, dataDir, "Sample traces")
+	if first.Key != "OBS-1" || second.Key != "OBS-2" {
+		t.Fatalf("keys = %s, %s; want OBS-1, OBS-2", first.Key, second.Key)
+	}
+	if first.Status != "To Do" {
+		t.Errorf("status = %q, want %q", first.Status, "To Do")
+	}
+
+	_, local, err := findTicketByKey(dataDir, newParser(dataDir), "OBS-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if local.EpicKey != "OBS-1" || local.Status != "To Do" {
+		t.Errorf("OBS-2 on disk has epic %q and status %q, want OBS-1 and To Do", local.EpicKey, local.Status)
+	}
+
+	// Losing the key counter must not hand out a key that is already in use
+	if err := os.Remove(filepath.Join(dataDir, "local_keys.json")); err != nil {
+		t.Fatal(err)
+	}
+	if third := createLocalTask(t, dataDir, "Trace sampling docs"); third.Key != "OBS-3" {
+		t.Errorf("key after losing local_keys.json = %s, want OBS-3", third.Key)
+	}
+}
+
+func TestKeyBackendLocalTransition(t *testing.T) {
+	dataDir := useLocalTracker(t, "OBS")
+	task := createLocalTask(t, dataDir, "Add spans")
+
+	backend, err := newKeyBackend(task.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := backend.Transition(task.Key, "In Review")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, local, err := findTicketByKey(dataDir, newParser(dataDir), task.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != "In Review" || local.Status != "In Review" {
+		t.Errorf("after transition: returned %q, stored %q; want In Review", status, local.Status)
+	}
+}
+
+func TestCreateTrackerTicketLocalRejectsBadPrefix(t *testing.T) {
+	useLocalTracker(t, "OBS2")
+
+	task := &types.Ticket{Type: types.TicketTypeTask, Title: "Add spans"}
+	if err := createTrackerTicket(task); err == nil {
+		t.Errorf("created %s with a prefix that isn't only letters", task.Key)
+	}
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lunchboxsushi/jai/internal/context"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var startCmd = &cobra.Command{
+	Use:   "start [key]",
+	Short: "Move the focused ticket to In Progress",
+	Long: `Transition the focused ticket (or the given key) to In Progress in Jira and
+update the Status line in its markdown file.
+
+The target status can be changed with the 'start' entry in jira.transition_aliases.
+
+Examples:
+  jai start                   # Start the focused subtask, task or epic
+  jai start SRE-1234          # Start a specific ticket`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTransition("start", args)
+	},
+}
+
+var doneCmd = &cobra.Command{
+	Use:   "done [key]",
+	Short: "Move the focused ticket to Done",
+	Long: `Transition the focused ticket (or the given key) to Done in Jira and update the
+Status line in its markdown file.
+
+The target status can be changed with the 'done' entry in jira.transition_aliases.
+
+Examples:
+  jai done                    # Close the focused subtask, task or epic
+  jai done SRE-1234           # Close a specific ticket`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTransition("done", args)
+	},
+}
+
+var moveCmd = &cobra.Command{
+	Use:   "move <status> [key]",
+	Short: "Move the focused ticket to another workflow status",
+	Long: `Transition the focused ticket (or the given key) in Jira. The status is matched
+against the names of the available transitions and the statuses they lead to,
+first exactly and then fuzzily, after resolving any alias in jira.transition_aliases.
+
+Examples:
+  jai move review             # Matches "In Review" or a "Review" transition
+  jai move "In Progress" SRE-1234
+  jai move blocked            # Uses jira.transition_aliases.blocked if configured`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTransition(args[0], args[1:])
+	},
+}
+
+// defaultTransitionAliases maps the built-in shortcuts to the usual Jira status names
+var defaultTransitionAliases = map[string]string{
+	"start": "In Progress",
+	"done":  "Done",
+}
+
+func init() {
+	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(doneCmd)
+	rootCmd.AddCommand(moveCmd)
+}
+
+func runTransition(target string, args []string) error {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
+	}
+
+	key, err := resolveTicketKey(dataDir, args)
+	if err != nil {
+		return err
+	}
+
+	backend, err := newKeyBackend(key)
+	if err != nil {
+		return err
+	}
+
+	toStatus, err := backend.Transition(key, resolveTransitionAlias(target))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s moved to %s\n", key, toStatus)
+
+	// Reflect the new status in the local markdown file
+	parser := newParser(dataDir)
+	filePath, ticket, err := findTicketByKey(dataDir, parser, key)
+	if err != nil {
+		fmt.Printf("Warning: %v, local status not updated\n", err)
+		return nil
+	}
+
+	ticket.Status = toStatus
+	if err := parser.UpdateTicket(filePath, *ticket); err != nil {
+		fmt.Printf("Warning: Failed to update status in %s: %v\n", filePath, err)
+	}
+
+	return nil
+}
+
+// resolveTicketKey returns the explicit key argument, or the most specific focused ticket
+func resolveTicketKey(dataDir string, args []string) (string, error) {
+	if len(args) > 0 {
+		return strings.ToUpper(strings.TrimSpace(args[0])), nil
+	}
+
+	ctxManager := context.NewManager(dataDir)
+	if err := ctxManager.Load(); err != nil {
+		return "", fmt.Errorf("failed to load context: %w", err)
+	}
+
+	switch {
+	case ctxManager.HasSubtask():
+		return ctxManager.GetSubtaskKey(), nil
+	case ctxManager.HasTask():
+		return ctxManager.GetTaskKey(), nil
+	case ctxManager.HasEpic():
+		return ctxManager.GetEpicKey(), nil
+	}
+
+	return "", fmt.Errorf("no ticket focused. Use 'jai focus <ticket>' or pass a key")
+}
+
+// resolveTransitionAlias maps a shortcut like "done" to the configured Jira status name
+func resolveTransitionAlias(target string) string {
+	// Viper lowercases map keys, so aliases are matched case-insensitively
+	aliases := viper.GetStringMapString("jira.transition_aliases")
+	if status, ok := aliases[strings.ToLower(target)]; ok && status != "" {
+		return status
+	}
+	if status, ok := defaultTransitionAliases[strings.ToLower(target)]; ok {
+		return status
+	}
+	return target
+}
@@ -3,25 +3,213 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 
+	"github.com/lunchboxsushi/jai/internal/jira"
 	"github.com/lunchboxsushi/jai/internal/markdown"
 	"github.com/lunchboxsushi/jai/internal/types"
+	"github.com/spf13/viper"
 )
 
 func isMarkdownFile(name string) bool {
 	return strings.HasSuffix(name, ".md") || strings.HasSuffix(name, ".markdown")
 }
 
-func findAllTickets(dataDir string, parser *markdown.Parser) ([]types.Ticket, error) {
+// openEditorWithTemplate opens the configured editor on a temp file seeded with template
+// and returns whatever the user saved
+func openEditorWithTemplate(pattern, template string) (string, error) {
+	// Get editor from config or environment
+	editor := viper.GetString("general.default_editor")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+		if editor == "" {
+			editor = "vim" // Default fallback
+		}
+	}
+
+	// Create temporary file
+	tmpFile, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	// Write template to temp file
+	if _, err := tmpFile.WriteString(template); err != nil {
+		return "", fmt.Errorf("failed to write template: %w", err)
+	}
+	tmpFile.Close()
+
+	// Open editor
+	cmd := exec.Command(editor, tmpFile.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run editor: %w", err)
+	}
+
+	// Read content back
+	content, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read temp file: %w", err)
+	}
+
+	return string(content), nil
+}
+
+// ticketFileName returns the SRE-####-{ticket title} style file name for a ticket
+func ticketFileName(key, title string) string {
+	// Convert title to filename-safe format
+	safeTitle := strings.ReplaceAll(title, " ", "-")
+	for _, ch := range []string{"/", "\\", ":", "*", "?", "\"", "<", ">", "|"} {
+		safeTitle = strings.ReplaceAll(safeTitle, ch, "-")
+	}
+
+	// Remove any double dashes and trim
+	safeTitle = strings.ReplaceAll(safeTitle, "--", "-")
+	safeTitle = strings.Trim(safeTitle, "-")
+
+	return fmt.Sprintf("%s-%s.md", key, safeTitle)
+}
+
+// getDataDir returns the configured data directory, falling back to the default location
+func getDataDir() (string, error) {
+	dataDir := viper.GetString("general.data_dir")
+	if dataDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dataDir = filepath.Join(home, ".local", "share", "jai")
+	}
+	return dataDir, nil
+}
+
+// expandHome expands a leading ~ in a configured path to the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// loadJiraConfig builds the application config for talking to Jira from viper and the
+// environment, using the active profile
+func loadJiraConfig() *types.Config {
+	config, err := loadJiraConfigFor(activeProfile())
+	if err != nil {
+		// The active profile was validated when the config was read
+		return &types.Config{}
+	}
+	return config
+}
+
+// loadJiraConfigFor builds the Jira config for a profile, or for the top-level jira settings if profile is ""
+func loadJiraConfigFor(profile string) (*types.Config, error) {
+	v, err := profileViper(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &types.Config{}
+	config.Jira.URL = v.GetString("jira.url")
+	config.Jira.Username = v.GetString("jira.username")
+	config.Jira.Token = jiraToken(profile)
+	config.Jira.Project = v.GetString("jira.project")
+	config.Jira.EpicLinkField = v.GetString("jira.epic_link_field")
+	config.Jira.PageSize = v.GetInt("jira.page_size")
+	config.Jira.BoardID = v.GetInt("jira.board_id")
+	config.Jira.CustomFields = v.GetStringMapString("jira.custom_fields")
+	config.Jira.ProjectStyle = v.GetString("jira.project_style")
+	config.Jira.APIVersion = v.GetString("jira.api_version")
+	config.Jira.Auth = v.GetString("jira.auth")
+	config.Jira.Timeout = int(jira.DefaultTimeout.Seconds())
+	if v.IsSet("jira.timeout") {
+		config.Jira.Timeout = v.GetInt("jira.timeout")
+	}
+	config.Jira.MaxRetries = jira.DefaultMaxRetries
+	if v.IsSet("jira.max_retries") {
+		config.Jira.MaxRetries = v.GetInt("jira.max_retries")
+	}
+	config.Jira.OAuth.TokenFile = expandHome(v.GetString("jira.oauth.token_file"))
+	config.Jira.OAuth.ClientID = v.GetString("jira.oauth.client_id")
+	config.Jira.OAuth.ClientSecret = os.Getenv("JAI_JIRA_OAUTH_SECRET")
+	config.Jira.OAuth.CloudID = v.GetString("jira.oauth.cloud_id")
+	config.General.DataDir, _ = getDataDir()
+	return config, nil
+}
+
+// newJiraClient creates a Jira client from the current configuration
+func newJiraClient() (*jira.Client, error) {
+	return newJiraClientWith(loadJiraConfig())
+}
+
+// newJiraClientWith creates a Jira client from an already loaded configuration
+func newJiraClientWith(config *types.Config) (*jira.Client, error) {
+	if missing := jira.CheckAuthConfig(config); len(missing) > 0 {
+		return nil, fmt.Errorf("Jira configuration incomplete: %s", strings.Join(missing, ", "))
+	}
+
+	jiraClient, err := jira.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Jira client: %w", err)
+	}
+
+	return jiraClient, nil
+}
+
+// newParser creates a markdown parser that reads the custom fields mapped in jira.custom_fields,
+// at the top level or in any profile, from a ticket's metadata section
+func newParser(dataDir string) *markdown.Parser {
+	mapped := mappedCustomFields()
+	return markdown.NewParser(dataDir).WithCustomFields(func(name string) bool {
+		return jira.CustomFieldID(mapped, name) != ""
+	})
+}
+
+var (
+	customFieldsOnce sync.Once
+	customFields     *types.Config
+)
+
+// mappedCustomFields returns a config holding every custom field mapped at the top level or
+// in a profile. The profiles are read on first use only, as the config doesn't change while
+// a command runs.
+func mappedCustomFields() *types.Config {
+	customFieldsOnce.Do(func() {
+		customFields = &types.Config{}
+		customFields.Jira.CustomFields = map[string]string{}
+		for _, profile := range append([]string{""}, profileNames()...) {
+			config, err := loadJiraConfigFor(profile)
+			if err != nil {
+				continue
+			}
+			for name, id := range config.Jira.CustomFields {
+				customFields.Jira.CustomFields[name] = id
+			}
+		}
+	})
+	return customFields
+}
+
+// findTicketFiles parses every markdown file in the tickets directory
+func findTicketFiles(dataDir string, parser *markdown.Parser) ([]*types.MarkdownFile, error) {
 	ticketsDir := filepath.Join(dataDir, "tickets")
 	files, err := os.ReadDir(ticketsDir)
 	if err != nil {
 		return nil, fmt.Errorf("could not read tickets directory: %w", err)
 	}
 
-	var allTickets []types.Ticket
+	var mdFiles []*types.MarkdownFile
 	for _, file := range files {
 		if file.IsDir() || !isMarkdownFile(file.Name()) {
 			continue
@@ -32,6 +220,38 @@ func findAllTickets(dataDir string, parser *markdown.Parser) ([]types.Ticket, er
 			// Log or handle error if a file can't be parsed
 			continue
 		}
+		mdFiles = append(mdFiles, mdFile)
+	}
+
+	return mdFiles, nil
+}
+
+// findTicketByKey returns the path of the markdown file holding the ticket with the given key
+func findTicketByKey(dataDir string, parser *markdown.Parser, key string) (string, *types.Ticket, error) {
+	mdFiles, err := findTicketFiles(dataDir, parser)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, mdFile := range mdFiles {
+		for _, ticket := range mdFile.Tickets {
+			if strings.EqualFold(ticket.Key, key) {
+				return mdFile.Path, &ticket, nil
+			}
+		}
+	}
+
+	return "", nil, fmt.Errorf("no local ticket found with key %s", key)
+}
+
+func findAllTickets(dataDir string, parser *markdown.Parser) ([]types.Ticket, error) {
+	mdFiles, err := findTicketFiles(dataDir, parser)
+	if err != nil {
+		return nil, err
+	}
+
+	var allTickets []types.Ticket
+	for _, mdFile := range mdFiles {
 		allTickets = append(allTickets, mdFile.Tickets...)
 	}
 
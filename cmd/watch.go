@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lunchboxsushi/jai/internal/jira"
+	"github.com/lunchboxsushi/jai/internal/markdown"
+	"github.com/lunchboxsushi/jai/internal/outbox"
+	"github.com/lunchboxsushi/jai/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Poll Jira and keep local ticket metadata fresh",
+	Long: `Poll Jira on an interval for the tickets in the tickets directory and apply
+changes to their status, priority, assignee, epic, parent and sprint as they happen,
+printing a line for each change. Unassigning a ticket or taking it out of a sprint in
+Jira clears the field locally too. Use it where Jira can't reach 'jai serve'.
+
+Each poll asks Jira for the local tickets by key, a hundred at a time, and only for
+those updated since the last poll. Tickets deleted in Jira or no longer visible are
+reported once and left out of later polls. The time of the last successful poll is kept
+in watch.json in the data directory, so a restarted watch picks up where it left off;
+the very first poll looks back a day.
+
+Only the tickets of the active profile are watched, since the others live on other Jira
+instances; without --profile that means the tickets that don't belong to a profile. Run
+one watch per profile to cover them all. Descriptions, links and custom fields are left
+to 'jai sync'.
+
+Examples:
+  jai watch                        # Poll every 2 minutes
+  jai watch --interval 30s
+  jai watch --profile work         # Watch the tickets of the work profile`,
+	Args: cobra.NoArgs,
+	RunE: runWatch,
+}
+
+var watchInterval time.Duration
+
+const (
+	// minWatchInterval keeps the watch from hammering Jira
+	minWatchInterval = 10 * time.Second
+
+	// firstPollLookback is how far back the first poll looks when there is no mark yet
+	firstPollLookback = 24 * time.Hour
+
+	// watchSkew is added to every lookback to cover clock differences with Jira
+	watchSkew = time.Minute
+)
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 2*time.Minute, "Time between polls")
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if err := requireJira("watch"); err != nil {
+		return err
+	}
+
+	if watchInterval < minWatchInterval {
+		return fmt.Errorf("--interval must be at least %s", minWatchInterval)
+	}
+
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
+	}
+
+	jiraClient, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	parser := newParser(dataDir)
+	marks := watch.NewMarks(dataDir)
+	if err := marks.Load(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Watching local tickets every %s (Ctrl+C to stop)\n", watchInterval)
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	skipped := make(map[string]bool)
+	for {
+		if err := pollTickets(jiraClient, parser, dataDir, marks, skipped); err != nil {
+			fmt.Printf("%s ✗ Poll failed: %s\n", time.Now().Format("15:04"), describeError(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("Stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollTickets asks Jira for the local tickets updated since the last poll and applies their
+// metadata. The mark only moves forward when the poll succeeds, so nothing is missed. Keys
+// Jira rejects are added to skipped and left out of later polls.
+func pollTickets(jiraClient *jira.Client, parser *markdown.Parser, dataDir string, marks *watch.Marks, skipped map[string]bool) error {
+	started := time.Now()
+
+	mdFiles, err := findTicketFiles(dataDir, parser)
+	if err != nil {
+		return err
+	}
+
+	watched := make(map[string]localTicket)
+	for _, mdFile := range mdFiles {
+		for _, ticket := range mdFile.Tickets {
+			// Tickets of other profiles live on other Jira instances
+			if ticket.Key == "" || outbox.IsPlaceholder(ticket.Key) || !belongsToProfile(ticket, activeProfile()) {
+				continue
+			}
+			watched[strings.ToUpper(ticket.Key)] = localTicket{path: mdFile.Path, ticket: ticket}
+		}
+	}
+	var keys []string
+	for key := range watched {
+		if !skipped[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+
+	// Relative dates avoid depending on the Jira user's time zone
+	lookback := firstPollLookback
+	if since := marks.Get(activeProfile()); !since.IsZero() {
+		lookback = started.Sub(since) + watchSkew
+	}
+	remotes, rejected, err := jiraClient.SearchByKeys(keys, fmt.Sprintf(`updated >= "-%dm"`, int(math.Ceil(lookback.Minutes()))))
+	if err != nil {
+		return err
+	}
+	for _, key := range rejected {
+		fmt.Printf("%s Warning: %s no longer exists in Jira or can't be seen; no longer watching it\n", time.Now().Format("15:04"), key)
+		skipped[key] = true
+	}
+
+	var changed int
+	for _, remote := range remotes {
+		local, ok := watched[strings.ToUpper(remote.Key)]
+		if !ok {
+			continue
+		}
+		ticket := local.ticket
+		changes := refreshMetadata(&ticket, remote, false)
+		if len(changes) == 0 {
+			continue
+		}
+		if err := parser.UpdateTicket(local.path, ticket); err != nil {
+			fmt.Printf("%s ✗ %s: failed to update %s: %v\n", time.Now().Format("15:04"), ticket.Key, local.path, err)
+			continue
+		}
+		fmt.Printf("%s ↻ %s %s: %s\n", time.Now().Format("15:04"), ticket.Key, parser.RemoveJiraKey(ticket.Title), strings.Join(changes, ", "))
+		changed++
+	}
+	if verbose {
+		fmt.Printf("%s Polled %d local ticket(s), %d changed\n", time.Now().Format("15:04"), len(watched), changed)
+	}
+
+	marks.Set(activeProfile(), started)
+	return marks.Save()
+}
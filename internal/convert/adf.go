@@ -0,0 +1,188 @@
+package convert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Node is a node in an Atlassian Document Format document (REST API v3)
+type Node struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []*Node                `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []*Mark                `json:"marks,omitempty"`
+}
+
+// Mark is inline formatting applied to an ADF text node
+type Mark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// MarkdownToADF converts ticket markdown into an ADF document
+func MarkdownToADF(md string) *Node {
+	ids := 0
+	return &Node{
+		Type:    "doc",
+		Version: 1,
+		Content: adfBlocks(parseMarkdown(md), &ids),
+	}
+}
+
+// adfBlocks converts markdown blocks to ADF block nodes; ids numbers task items
+func adfBlocks(blocks []block, ids *int) []*Node {
+	var nodes []*Node
+	for _, b := range blocks {
+		switch b.kind {
+		case blockHeading:
+			nodes = append(nodes, &Node{
+				Type:    "heading",
+				Attrs:   map[string]interface{}{"level": b.level},
+				Content: adfInline(b.text),
+			})
+
+		case blockList:
+			// ADF can't mix task and plain items, so a change of kind starts a new list
+			for i := 0; i < len(b.items); {
+				var list *Node
+				list, i = adfList(b.items, i, ids)
+				nodes = append(nodes, list)
+			}
+
+		case blockCode:
+			code := &Node{Type: "codeBlock"}
+			if b.lang != "" {
+				code.Attrs = map[string]interface{}{"language": b.lang}
+			}
+			if b.text != "" {
+				code.Content = []*Node{{Type: "text", Text: b.text}}
+			}
+			nodes = append(nodes, code)
+
+		case blockQuote:
+			nodes = append(nodes, &Node{
+				Type:    "blockquote",
+				Content: adfBlocks(parseMarkdown(b.text), ids),
+			})
+
+		case blockRule:
+			nodes = append(nodes, &Node{Type: "rule"})
+
+		case blockTable:
+			table := &Node{Type: "table"}
+			for i, row := range b.rows {
+				cellType := "tableCell"
+				if i == 0 {
+					cellType = "tableHeader"
+				}
+				tableRow := &Node{Type: "tableRow"}
+				for _, cell := range row {
+					tableRow.Content = append(tableRow.Content, &Node{
+						Type:    cellType,
+						Content: []*Node{{Type: "paragraph", Content: adfInline(cell)}},
+					})
+				}
+				table.Content = append(table.Content, tableRow)
+			}
+			nodes = append(nodes, table)
+
+		default:
+			nodes = append(nodes, &Node{Type: "paragraph", Content: adfInline(b.text)})
+		}
+	}
+	return nodes
+}
+
+// adfList builds a (possibly nested) list from items[start:] at the depth of items[start],
+// returning the list and the index of the first item that doesn't belong to it
+func adfList(items []listItem, start int, ids *int) (*Node, int) {
+	depth := items[start].depth
+	list := &Node{Type: "bulletList"}
+	switch {
+	case items[start].task:
+		list.Type = "taskList"
+		*ids++
+		list.Attrs = map[string]interface{}{"localId": fmt.Sprintf("list-%d", *ids)}
+	case items[start].ordered:
+		list.Type = "orderedList"
+	}
+
+	i := start
+	for i < len(items) && items[i].depth == depth && items[i].task == items[start].task {
+		item := items[i]
+		var entry *Node
+		if list.Type == "taskList" {
+			state := "TODO"
+			if item.checked {
+				state = "DONE"
+			}
+			*ids++
+			entry = &Node{
+				Type:    "taskItem",
+				Attrs:   map[string]interface{}{"localId": fmt.Sprintf("task-%d", *ids), "state": state},
+				Content: adfInline(item.text),
+			}
+		} else {
+			entry = &Node{
+				Type:    "listItem",
+				Content: []*Node{{Type: "paragraph", Content: adfInline(item.text)}},
+			}
+		}
+		list.Content = append(list.Content, entry)
+		i++
+
+		// Deeper items nest inside the entry (or beside it, for task lists)
+		for i < len(items) && items[i].depth > depth {
+			var child *Node
+			child, i = adfList(items, i, ids)
+			if list.Type == "taskList" {
+				list.Content = append(list.Content, child)
+			} else {
+				entry.Content = append(entry.Content, child)
+			}
+		}
+	}
+
+	return list, i
+}
+
+// adfInline converts markdown inline text into ADF text nodes
+func adfInline(s string) []*Node {
+	var nodes []*Node
+	for _, sp := range parseInline(s) {
+		var marks []*Mark
+		if sp.code {
+			marks = append(marks, &Mark{Type: "code"})
+		}
+		if sp.strong {
+			marks = append(marks, &Mark{Type: "strong"})
+		}
+		if sp.em {
+			marks = append(marks, &Mark{Type: "em"})
+		}
+		if sp.strike {
+			marks = append(marks, &Mark{Type: "strike"})
+		}
+		if sp.href != "" {
+			marks = append(marks, &Mark{Type: "link", Attrs: map[string]interface{}{"href": sp.href}})
+		}
+
+		text := sp.text
+		if sp.image && text == "" {
+			text = sp.href
+		}
+
+		// Line breaks inside a paragraph become hard breaks
+		for i, line := range strings.Split(text, "\n") {
+			if i > 0 {
+				nodes = append(nodes, &Node{Type: "hardBreak"})
+			}
+			if line != "" {
+				nodes = append(nodes, &Node{Type: "text", Text: line, Marks: marks})
+			}
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,24 @@
+package convert
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMarkdownToADF(t *testing.T) {
+	doc := MarkdownToADF("## Plan\n\n- [ ] write docs\n- [x] ship\n\nUse **care** with 2*3*4")
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"doc","version":1,"content":[` +
+		`{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Plan"}]},` +
+		`{"type":"taskList","attrs":{"localId":"list-1"},"content":[` +
+		`{"type":"taskItem","attrs":{"localId":"task-2","state":"TODO"},"content":[{"type":"text","text":"write docs"}]},` +
+		`{"type":"taskItem","attrs":{"localId":"task-3","state":"DONE"},"content":[{"type":"text","text":"ship"}]}]},` +
+		`{"type":"paragraph","content":[{"type":"text","text":"Use "},{"type":"text","text":"care","marks":[{"type":"strong"}]},{"type":"text","text":" with 2*3*4"}]}]}`
+	if string(data) != want {
+		t.Errorf("MarkdownToADF:\n got %s\nwant %s", data, want)
+	}
+}
@@ -0,0 +1,59 @@
+package convert
+
+import (
+	"regexp"
+	"strings"
+)
+
+// markdownLinkRe matches markdown links and images, capturing the "!", text and target
+var markdownLinkRe = regexp.MustCompile(`(!?)\[([^\]]*)\]\(([^)\s]+)\)`)
+
+// RewriteLinks replaces the target of every link and image in a markdown document.
+// rewrite returns the new target, or "" to leave the link alone. Code blocks are skipped.
+func RewriteLinks(markdown string, rewrite func(target string, image bool) string) string {
+	lines := strings.Split(markdown, "\n")
+	inFence := false
+	for i, line := range lines {
+		if fenceRe.MatchString(strings.TrimSpace(line)) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		lines[i] = markdownLinkRe.ReplaceAllStringFunc(line, func(link string) string {
+			m := markdownLinkRe.FindStringSubmatch(link)
+			target := rewrite(m[3], m[1] == "!")
+			if target == "" {
+				return link
+			}
+			return m[1] + "[" + m[2] + "](" + target + ")"
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LocalFileLinks returns the targets of links and images that point at local files by
+// path, e.g. "./img/trace.png", in the order they first appear
+func LocalFileLinks(markdown string) []string {
+	var targets []string
+	seen := make(map[string]bool)
+	RewriteLinks(markdown, func(target string, image bool) string {
+		if IsLocalFileLink(target) && !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+		return ""
+	})
+	return targets
+}
+
+// IsLocalFileLink reports whether a link target is an explicit path to a local file
+func IsLocalFileLink(target string) bool {
+	for _, prefix := range []string{"./", "../", "/", "~/"} {
+		if strings.HasPrefix(target, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,328 @@
+package convert
+
+import (
+	"regexp"
+	"strings"
+)
+
+// blockKind identifies a block-level markdown element
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockList
+	blockCode
+	blockQuote
+	blockRule
+	blockTable
+)
+
+// block is a block-level element of a markdown document
+type block struct {
+	kind  blockKind
+	level int        // Heading level
+	text  string     // Paragraph, heading and quote text, or code body
+	lang  string     // Code block language
+	items []listItem // List items, flattened with their nesting depth
+	rows  [][]string // Table rows, the first one being the header
+}
+
+// listItem is a single list entry
+type listItem struct {
+	depth   int
+	ordered bool
+	task    bool
+	checked bool
+	text    string
+}
+
+// span is a run of inline text sharing the same formatting
+type span struct {
+	text   string
+	strong bool
+	em     bool
+	code   bool
+	strike bool
+	href   string
+	image  bool
+}
+
+var (
+	headingRe  = regexp.MustCompile(`^(#{1,6})\s+(.*?)(\s+#+)?\s*$`)
+	fenceRe    = regexp.MustCompile("^(```+|~~~+)\\s*([\\w+#.-]*)\\s*$")
+	listItemRe = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	taskRe     = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	ruleRe     = regexp.MustCompile(`^(-(\s*-){2,}|\*(\s*\*){2,}|_(\s*_){2,})$`)
+	tableSepRe = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+)
+
+// parseMarkdown splits a markdown document into blocks
+func parseMarkdown(md string) []block {
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+
+	var blocks []block
+	var para []string
+	flushPara := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, block{kind: blockParagraph, text: strings.Join(para, "\n")})
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flushPara()
+
+		case fenceRe.MatchString(trimmed):
+			flushPara()
+			m := fenceRe.FindStringSubmatch(trimmed)
+			var code []string
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != m[1]; i++ {
+				code = append(code, lines[i])
+			}
+			blocks = append(blocks, block{kind: blockCode, lang: m[2], text: strings.Join(code, "\n")})
+
+		case headingRe.MatchString(trimmed):
+			flushPara()
+			m := headingRe.FindStringSubmatch(trimmed)
+			blocks = append(blocks, block{kind: blockHeading, level: len(m[1]), text: m[2]})
+
+		case ruleRe.MatchString(trimmed):
+			flushPara()
+			blocks = append(blocks, block{kind: blockRule})
+
+		case listItemRe.MatchString(line):
+			flushPara()
+			var items []listItem
+			var indents []int
+			for ; i < len(lines) && listItemRe.MatchString(lines[i]); i++ {
+				m := listItemRe.FindStringSubmatch(lines[i])
+				indent := len(strings.ReplaceAll(m[1], "\t", "    "))
+
+				// Nesting follows indentation relative to the enclosing items
+				for len(indents) > 0 && indent < indents[len(indents)-1] {
+					indents = indents[:len(indents)-1]
+				}
+				if len(indents) == 0 || indent > indents[len(indents)-1] {
+					indents = append(indents, indent)
+				}
+
+				item := listItem{
+					depth:   len(indents) - 1,
+					ordered: m[2][0] >= '0' && m[2][0] <= '9',
+					text:    m[3],
+				}
+				if t := taskRe.FindStringSubmatch(item.text); t != nil && !item.ordered {
+					item.task = true
+					item.checked = t[1] != " "
+					item.text = t[2]
+				}
+				items = append(items, item)
+			}
+			i--
+			blocks = append(blocks, block{kind: blockList, items: items})
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushPara()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quoted := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quote = append(quote, strings.TrimPrefix(quoted, " "))
+			}
+			i--
+			blocks = append(blocks, block{kind: blockQuote, text: strings.Join(quote, "\n")})
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && tableSepRe.MatchString(strings.TrimSpace(lines[i+1])):
+			flushPara()
+			rows := [][]string{splitTableRow(trimmed)}
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				rows = append(rows, splitTableRow(strings.TrimSpace(lines[i])))
+			}
+			i--
+			blocks = append(blocks, block{kind: blockTable, rows: rows})
+
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flushPara()
+
+	return blocks
+}
+
+// splitTableRow splits "| a | b |" into its trimmed cells
+func splitTableRow(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	cells := strings.Split(row, "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+	return cells
+}
+
+// parseInline splits markdown inline text into formatted spans
+func parseInline(s string) []span {
+	return parseInlineWith(s, span{})
+}
+
+// parseInlineWith parses s with the formatting of marks applied to every span
+func parseInlineWith(s string, marks span) []span {
+	var spans []span
+	var plain strings.Builder
+	emit := func() {
+		if plain.Len() > 0 {
+			sp := marks
+			sp.text = plain.String()
+			spans = append(spans, sp)
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && strings.ContainsRune("\\`*_~[]()#!|-", rune(s[i+1])):
+			plain.WriteByte(s[i+1])
+			i += 2
+
+		case s[i] == '`':
+			end := strings.IndexByte(s[i+1:], '`')
+			if end < 0 {
+				plain.WriteByte(s[i])
+				i++
+				continue
+			}
+			emit()
+			sp := marks
+			sp.code = true
+			sp.text = s[i+1 : i+1+end]
+			spans = append(spans, sp)
+			i += end + 2
+
+		case strings.HasPrefix(s[i:], "**") || strings.HasPrefix(s[i:], "__") || strings.HasPrefix(s[i:], "~~"):
+			delim := s[i : i+2]
+			end := strings.Index(s[i+2:], delim)
+			if end <= 0 {
+				plain.WriteString(delim)
+				i += 2
+				continue
+			}
+			emit()
+			inner := marks
+			if delim == "~~" {
+				inner.strike = true
+			} else {
+				inner.strong = true
+			}
+			spans = append(spans, parseInlineWith(s[i+2:i+2+end], inner)...)
+			i += end + 4
+
+		case (s[i] == '*' || s[i] == '_') && canOpenEmphasis(s, i):
+			end := findEmphasisClose(s, i+1, s[i])
+			if end < 0 {
+				plain.WriteByte(s[i])
+				i++
+				continue
+			}
+			emit()
+			inner := marks
+			inner.em = true
+			spans = append(spans, parseInlineWith(s[i+1:end], inner)...)
+			i = end + 1
+
+		case s[i] == '[' || (s[i] == '!' && strings.HasPrefix(s[i+1:], "[")):
+			image := s[i] == '!'
+			start := i
+			if image {
+				start++
+			}
+			text, href, n, ok := parseLink(s[start:])
+			if !ok {
+				plain.WriteByte(s[i])
+				i++
+				continue
+			}
+			emit()
+			inner := marks
+			inner.href = href
+			if image {
+				inner.image = true
+				inner.text = text
+				spans = append(spans, inner)
+			} else {
+				spans = append(spans, parseInlineWith(text, inner)...)
+			}
+			i = start + n
+
+		default:
+			plain.WriteByte(s[i])
+			i++
+		}
+	}
+	emit()
+
+	return spans
+}
+
+// canOpenEmphasis reports whether the * or _ at i can start emphasis
+func canOpenEmphasis(s string, i int) bool {
+	if i+1 >= len(s) || s[i+1] == ' ' || s[i+1] == s[i] {
+		return false
+	}
+	// Delimiters inside a word, as in snake_case or 2*3*4, are literal: Jira doesn't
+	// format inside words, so they couldn't be sent as emphasis anyway
+	return i == 0 || !isWordChar(s[i-1])
+}
+
+// findEmphasisClose returns the index of the delimiter closing emphasis opened before from
+func findEmphasisClose(s string, from int, delim byte) int {
+	for j := from + 1; j < len(s); j++ {
+		if s[j] != delim || s[j-1] == ' ' {
+			continue
+		}
+		if j+1 < len(s) && isWordChar(s[j+1]) {
+			continue
+		}
+		if delim == '*' && j+1 < len(s) && s[j+1] == '*' {
+			j++
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+// parseLink parses "[text](href)" at the start of s and returns the bytes consumed
+func parseLink(s string) (string, string, int, bool) {
+	closeText := strings.Index(s, "](")
+	if !strings.HasPrefix(s, "[") || closeText < 0 {
+		return "", "", 0, false
+	}
+	closeHref := strings.IndexByte(s[closeText+2:], ')')
+	if closeHref < 0 {
+		return "", "", 0, false
+	}
+	text := s[1:closeText]
+	href := strings.TrimSpace(s[closeText+2 : closeText+2+closeHref])
+	return text, href, closeText + 3 + closeHref, true
+}
+
+// isWordChar reports whether c is an ASCII letter, digit or underscore
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// SplitList splits a comma-separated metadata value, dropping empty items
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
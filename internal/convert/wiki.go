@@ -0,0 +1,331 @@
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Jira wiki markup has no checkboxes, so task list items keep their boxes as escaped
+// brackets, which is also how Jira's editor stores a typed "[ ]". The (/) and (x) icons
+// are left alone, since they are ordinary emoticons in Jira.
+const (
+	wikiTaskDone = `\[x\]`
+	wikiTaskTodo = `\[ \]`
+)
+
+var (
+	wikiHeadingRe  = regexp.MustCompile(`^h([1-6])\.\s+(.*)$`)
+	wikiCodeOpenRe = regexp.MustCompile(`^\{(code|noformat)(?::([^}]*))?\}(.*)$`)
+	wikiListRe     = regexp.MustCompile(`^([*#]+|-)\s+(.*)$`)
+	wikiRuleRe     = regexp.MustCompile(`^-{4,}$`)
+	wikiCodeSpanRe = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	wikiLinkRe     = regexp.MustCompile(`\[([^|\]]+)\|([^\]]+)\]`)
+	wikiBareLinkRe = regexp.MustCompile(`\[((?:https?|mailto):[^\]]+)\]`)
+	wikiImageRe    = regexp.MustCompile(`!([^!\s|]+)(?:\|[^!]*)?!`)
+	wikiStrongRe   = regexp.MustCompile(`(^|[\s(\[>])\*(\S|\S[^*]*?\S)\*($|[\s).,:;!?\]<])`)
+	wikiStrikeRe   = regexp.MustCompile(`(^|[\s(])-(\S|\S[^-]*?\S)-($|[\s).,:;!?])`)
+	placeholderRe  = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// MarkdownToWiki converts ticket markdown into Jira wiki markup (REST API v2)
+func MarkdownToWiki(md string) string {
+	var out []string
+	for _, b := range parseMarkdown(md) {
+		out = append(out, renderWikiBlock(b))
+	}
+	return strings.Join(out, "\n\n")
+}
+
+// renderWikiBlock renders a single markdown block as wiki markup
+func renderWikiBlock(b block) string {
+	switch b.kind {
+	case blockHeading:
+		return fmt.Sprintf("h%d. %s", b.level, renderWikiInline(b.text))
+
+	case blockList:
+		var lines []string
+		var kinds []byte
+		for _, item := range b.items {
+			marker := byte('*')
+			if item.ordered {
+				marker = '#'
+			}
+			if item.depth < len(kinds) {
+				kinds = kinds[:item.depth]
+			}
+			for len(kinds) < item.depth {
+				kinds = append(kinds, '*')
+			}
+			kinds = append(kinds, marker)
+
+			text := renderWikiInline(item.text)
+			if item.task {
+				box := wikiTaskTodo
+				if item.checked {
+					box = wikiTaskDone
+				}
+				text = box + " " + text
+			}
+			lines = append(lines, string(kinds)+" "+text)
+		}
+		return strings.Join(lines, "\n")
+
+	case blockCode:
+		open := "{code}"
+		if b.lang != "" {
+			open = fmt.Sprintf("{code:%s}", b.lang)
+		}
+		return open + "\n" + b.text + "\n{code}"
+
+	case blockQuote:
+		inner := MarkdownToWiki(b.text)
+		if !strings.Contains(inner, "\n") {
+			return "bq. " + inner
+		}
+		return "{quote}\n" + inner + "\n{quote}"
+
+	case blockRule:
+		return "----"
+
+	case blockTable:
+		var lines []string
+		for i, row := range b.rows {
+			sep := "|"
+			if i == 0 {
+				sep = "||"
+			}
+			cells := make([]string, len(row))
+			for j, cell := range row {
+				cells[j] = renderWikiInline(cell)
+			}
+			lines = append(lines, sep+strings.Join(cells, sep)+sep)
+		}
+		return strings.Join(lines, "\n")
+
+	default:
+		return renderWikiInline(b.text)
+	}
+}
+
+// renderWikiInline renders markdown inline text as wiki markup
+func renderWikiInline(s string) string {
+	spans := parseInline(s)
+
+	var out strings.Builder
+	for i := 0; i < len(spans); i++ {
+		sp := spans[i]
+		switch {
+		case sp.image:
+			out.WriteString("!" + sp.href + "!")
+		case sp.href != "":
+			// Consecutive spans of the same link share one [text|href]
+			var text strings.Builder
+			for ; i < len(spans) && spans[i].href == sp.href && !spans[i].image; i++ {
+				text.WriteString(wrapWikiSpan(spans[i]))
+			}
+			i--
+			out.WriteString("[" + text.String() + "|" + sp.href + "]")
+		default:
+			out.WriteString(wrapWikiSpan(sp))
+		}
+	}
+	return out.String()
+}
+
+// wrapWikiSpan applies a span's formatting in wiki syntax
+func wrapWikiSpan(sp span) string {
+	if sp.code {
+		return "{{" + sp.text + "}}"
+	}
+	text := sp.text
+	if sp.strike {
+		text = "-" + text + "-"
+	}
+	if sp.em {
+		text = "_" + text + "_"
+	}
+	if sp.strong {
+		text = "*" + text + "*"
+	}
+	return text
+}
+
+// WikiToMarkdown converts Jira wiki markup into ticket markdown
+func WikiToMarkdown(wiki string) string {
+	lines := strings.Split(strings.ReplaceAll(wiki, "\r\n", "\n"), "\n")
+
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case wikiCodeOpenRe.MatchString(trimmed):
+			m := wikiCodeOpenRe.FindStringSubmatch(trimmed)
+			closing := "{" + m[1] + "}"
+			out = append(out, "```"+wikiCodeLanguage(m[1], m[2]))
+
+			// Code may start on the same line as the opening macro
+			rest := m[3]
+			for {
+				if idx := strings.Index(rest, closing); idx >= 0 {
+					if before := rest[:idx]; strings.TrimSpace(before) != "" {
+						out = append(out, before)
+					}
+					break
+				}
+				if rest != "" {
+					out = append(out, rest)
+				}
+				i++
+				if i >= len(lines) {
+					break
+				}
+				rest = lines[i]
+			}
+			out = append(out, "```")
+
+		case trimmed == "{quote}":
+			var quote []string
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "{quote}"; i++ {
+				quote = append(quote, lines[i])
+			}
+			for _, q := range strings.Split(WikiToMarkdown(strings.Join(quote, "\n")), "\n") {
+				out = append(out, strings.TrimRight("> "+q, " "))
+			}
+
+		case wikiHeadingRe.MatchString(trimmed):
+			m := wikiHeadingRe.FindStringSubmatch(trimmed)
+			level := int(m[1][0] - '0')
+			out = append(out, strings.Repeat("#", level)+" "+wikiInlineToMarkdown(m[2]))
+
+		case strings.HasPrefix(trimmed, "bq. "):
+			out = append(out, "> "+wikiInlineToMarkdown(strings.TrimPrefix(trimmed, "bq. ")))
+
+		case wikiRuleRe.MatchString(trimmed):
+			out = append(out, "---")
+
+		case wikiListRe.MatchString(trimmed):
+			m := wikiListRe.FindStringSubmatch(trimmed)
+			markers := m[1]
+			if markers == "-" {
+				markers = "*"
+			}
+
+			// Indent under each enclosing item's marker width
+			var indent strings.Builder
+			for _, parent := range markers[:len(markers)-1] {
+				if parent == '#' {
+					indent.WriteString("   ")
+				} else {
+					indent.WriteString("  ")
+				}
+			}
+
+			text := m[2]
+			marker := "-"
+			if markers[len(markers)-1] == '#' {
+				marker = "1."
+			} else if strings.HasPrefix(text, wikiTaskDone+" ") || strings.HasPrefix(text, `\[X\] `) {
+				marker = "- [x]"
+				text = text[len(wikiTaskDone)+1:]
+			} else if strings.HasPrefix(text, wikiTaskTodo+" ") {
+				marker = "- [ ]"
+				text = strings.TrimPrefix(text, wikiTaskTodo+" ")
+			}
+			out = append(out, indent.String()+marker+" "+wikiInlineToMarkdown(text))
+
+		case strings.HasPrefix(trimmed, "|"):
+			var rows [][]string
+			header := strings.HasPrefix(trimmed, "||")
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				rows = append(rows, splitWikiTableRow(strings.TrimSpace(lines[i])))
+			}
+			i--
+
+			// Markdown tables always have a header row
+			if !header {
+				rows = append([][]string{make([]string, len(rows[0]))}, rows...)
+			}
+			for r, row := range rows {
+				cells := make([]string, len(row))
+				for c, cell := range row {
+					cells[c] = wikiInlineToMarkdown(cell)
+				}
+				out = append(out, "| "+strings.Join(cells, " | ")+" |")
+				if r == 0 {
+					seps := make([]string, len(row))
+					for c := range seps {
+						seps[c] = "---"
+					}
+					out = append(out, "| "+strings.Join(seps, " | ")+" |")
+				}
+			}
+
+		default:
+			out = append(out, wikiInlineToMarkdown(strings.TrimRight(line, " \t")))
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// wikiCodeLanguage extracts the language from {code:go} or {code:language=go|title=x}
+func wikiCodeLanguage(macro, params string) string {
+	if macro != "code" || params == "" {
+		return ""
+	}
+	for _, param := range strings.Split(params, "|") {
+		if strings.HasPrefix(param, "language=") {
+			return strings.TrimPrefix(param, "language=")
+		}
+		if !strings.Contains(param, "=") {
+			return param
+		}
+	}
+	return ""
+}
+
+// splitWikiTableRow splits "||a||b||" or "|a|b|" into its trimmed cells
+func splitWikiTableRow(row string) []string {
+	row = strings.ReplaceAll(row, "||", "|")
+	return splitTableRow(row)
+}
+
+// wikiInlineToMarkdown converts wiki inline formatting to markdown
+func wikiInlineToMarkdown(s string) string {
+	// Protect code spans so their content isn't reformatted
+	var code []string
+	s = wikiCodeSpanRe.ReplaceAllStringFunc(s, func(m string) string {
+		code = append(code, "`"+wikiCodeSpanRe.FindStringSubmatch(m)[1]+"`")
+		return fmt.Sprintf("\x00%d\x00", len(code)-1)
+	})
+
+	s = wikiImageRe.ReplaceAllString(s, "![]($1)")
+	s = wikiLinkRe.ReplaceAllString(s, "[$1]($2)")
+	s = wikiBareLinkRe.ReplaceAllString(s, "<$1>")
+	// Mark with control characters first so repeated passes can't match their own output
+	s = replaceAllRepeated(wikiStrongRe, s, "$1\x01$2\x01$3")
+	s = replaceAllRepeated(wikiStrikeRe, s, "$1\x02$2\x02$3")
+	s = strings.NewReplacer("\x01", "**", "\x02", "~~", `\\`, "\n").Replace(s)
+
+	return placeholderRe.ReplaceAllStringFunc(s, func(m string) string {
+		var n int
+		fmt.Sscanf(placeholderRe.FindStringSubmatch(m)[1], "%d", &n)
+		return code[n]
+	})
+}
+
+// replaceAllRepeated applies re until nothing changes, since matches sharing a
+// boundary character can't be replaced in a single pass
+func replaceAllRepeated(re *regexp.Regexp, s, repl string) string {
+	for i := 0; i < 8; i++ {
+		next := re.ReplaceAllString(s, repl)
+		if next == s {
+			break
+		}
+		s = next
+	}
+	return s
+}
@@ -0,0 +1,81 @@
+package convert
+
+import "testing"
+
+func TestMarkdownToWiki(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		wiki string
+	}{
+		{"heading", "## Rollout plan", "h2. Rollout plan"},
+		{"bullets", "- one\n- two\n  - nested", "* one\n* two\n** nested"},
+		{"ordered", "1. first\n2. second", "# first\n# second"},
+		{"task list", "- [ ] write docs\n- [x] ship", `* \[ \] write docs` + "\n" + `* \[x\] ship`},
+		{"code fence", "```go\nfmt.Println(\"*hi*\")\n```", "{code:go}\nfmt.Println(\"*hi*\")\n{code}"},
+		{"link", "See [the runbook](https://example.com/run) first", "See [the runbook|https://example.com/run] first"},
+		{"table", "| a | b |\n| --- | --- |\n| 1 | 2 |", "||a||b||\n|1|2|"},
+		{"emphasis", "a **strong** and _em_ word", "a *strong* and _em_ word"},
+		{"emphasis inside words", "2*3*4 and snake_case_name", "2*3*4 and snake_case_name"},
+		{"inline code", "run `make *all*`", "run {{make *all*}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarkdownToWiki(tt.md); got != tt.wiki {
+				t.Errorf("MarkdownToWiki(%q) = %q, want %q", tt.md, got, tt.wiki)
+			}
+		})
+	}
+}
+
+func TestWikiToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		wiki string
+		md   string
+	}{
+		{"icons stay text", "* (x) broken\n* (/) fixed", "- (x) broken\n- (/) fixed"},
+		{"escaped boxes", `* \[X\] done`, "- [x] done"},
+		{"noformat", "{noformat}a *b*{noformat}", "```\na *b*\n```"},
+		{"table without header", "|1|2|", "|  |  |\n| --- | --- |\n| 1 | 2 |"},
+		{"bare link", "[https://example.com]", "<https://example.com>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WikiToMarkdown(tt.wiki); got != tt.md {
+				t.Errorf("WikiToMarkdown(%q) = %q, want %q", tt.wiki, got, tt.md)
+			}
+		})
+	}
+}
+
+func TestWikiRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+	}{
+		{"headings", "# Title\n\n### Details"},
+		{"lists", "- one\n- two\n  - nested\n    - deeper\n- three"},
+		{"ordered lists", "1. first\n1. second\n   - detail"},
+		{"task lists", "- [ ] write docs\n- [x] ship it\n  - [ ] nested follow-up"},
+		{"code fences", "```go\nfunc main() {\n\t_ = a*b*c\n}\n```\n\n```\nplain\n```"},
+		{"links", "See [the runbook](https://example.com/run) and ![](diagram.png)"},
+		{"tables", "| Service | Owner |\n| --- | --- |\n| api | **platform** |"},
+		{"emphasis", "A **strong** word, an _emphasized_ one and ~~struck~~ text"},
+		{"emphasis inside text", "2*3*4 stays, as does snake_case_name and file_name.go"},
+		{"quotes", "> quoted line"},
+		{"rule", "above\n\n---\n\nbelow"},
+		{"icons", "- (x) broken build\n- (/) fixed build"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wiki := MarkdownToWiki(tt.md)
+			if got := WikiToMarkdown(wiki); got != tt.md {
+				t.Errorf("round trip through %q:\n got %q\nwant %q", wiki, got, tt.md)
+			}
+		})
+	}
+}
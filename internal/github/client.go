@@ -0,0 +1,329 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jai/internal/tracker"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// DefaultURL is the GitHub REST API used when tracker.github.url is not set
+const DefaultURL = "https://api.github.com"
+
+// Key prefixes: issues are GH-<number> and milestones, which stand in for epics, GHM-<number>
+const (
+	IssuePrefix     = "GH"
+	MilestonePrefix = "GHM"
+)
+
+const (
+	// searchPageSize is the number of issues requested per search page, GitHub's maximum
+	searchPageSize = 100
+
+	// maxSearchResults is the most results GitHub returns for a single search
+	maxSearchResults = 1000
+)
+
+var _ tracker.Backend = (*Client)(nil)
+
+// Client handles GitHub Issues API interactions. Epics are milestones, tasks are issues in
+// the epic's milestone, and subtasks are sub-issues of their task.
+type Client struct {
+	api  *tracker.APIClient
+	repo string // owner/name
+}
+
+// NewClient creates a new GitHub client for the configured repository
+func NewClient(config *types.Config) (*Client, error) {
+	repo := strings.Trim(config.Tracker.GitHub.Repo, "/")
+	if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" {
+		return nil, fmt.Errorf("tracker.github.repo must be owner/name, got %q", config.Tracker.GitHub.Repo)
+	}
+
+	baseURL := config.Tracker.GitHub.URL
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if token := config.Tracker.GitHub.Token; token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	return &Client{
+		api:  tracker.NewAPIClient("GitHub", baseURL, header, errorDetails),
+		repo: repo,
+	}, nil
+}
+
+// issue is a GitHub issue as returned by the REST API
+type issue struct {
+	ID        int64      `json:"id"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	State     string     `json:"state"`
+	Labels    []label    `json:"labels"`
+	Assignees []user     `json:"assignees"`
+	Milestone *milestone `json:"milestone"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// milestone is a GitHub milestone as returned by the REST API
+type milestone struct {
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	DueOn       *time.Time `json:"due_on"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type label struct {
+	Name string `json:"name"`
+}
+
+type user struct {
+	Login string `json:"login"`
+}
+
+// CreateTicket creates a milestone for an epic, or an issue for a task or subtask. Subtasks
+// are attached to their task as sub-issues, or as a task list item where sub-issues
+// aren't available.
+func (c *Client) CreateTicket(ticket *types.Ticket) (*types.Ticket, error) {
+	created := *ticket
+
+	if ticket.Type == types.TicketTypeEpic {
+		request := map[string]interface{}{
+			"title":       ticket.Title,
+			"description": tracker.Description(ticket),
+		}
+		if ticket.DueDate != nil {
+			request["due_on"] = ticket.DueDate.UTC().Format(time.RFC3339)
+		}
+
+		var m milestone
+		if err := c.api.Do(http.MethodPost, c.repoPath("milestones"), request, &m); err != nil {
+			return nil, fmt.Errorf("failed to create GitHub milestone: %w", err)
+		}
+		created.Key = fmt.Sprintf("%s-%d", MilestonePrefix, m.Number)
+		created.ID = strconv.Itoa(m.Number)
+		created.Status = tracker.OpenClosedStatus(m.State)
+		created.Created = time.Now()
+		created.Updated = time.Now()
+		return &created, nil
+	}
+
+	request := map[string]interface{}{
+		"title": ticket.Title,
+		"body":  tracker.Description(ticket),
+	}
+	if len(ticket.Labels) > 0 {
+		request["labels"] = ticket.Labels
+	}
+	if number, ok := tracker.KeyNumber(ticket.EpicKey, MilestonePrefix); ok {
+		request["milestone"] = number
+	}
+
+	var is issue
+	if err := c.api.Do(http.MethodPost, c.repoPath("issues"), request, &is); err != nil {
+		return nil, fmt.Errorf("failed to create GitHub issue: %w", err)
+	}
+	created.Key = fmt.Sprintf("%s-%d", IssuePrefix, is.Number)
+	created.ID = strconv.FormatInt(is.ID, 10)
+	created.Status = tracker.OpenClosedStatus(is.State)
+	created.Created = time.Now()
+	created.Updated = time.Now()
+
+	if parent, ok := tracker.KeyNumber(ticket.ParentKey, IssuePrefix); ok && ticket.Type == types.TicketTypeSubtask {
+		if err := c.attachSubtask(parent, &is); err != nil {
+			log.Printf("Warning: Created %s but failed to attach it to %s: %v", created.Key, ticket.ParentKey, err)
+		}
+	}
+
+	return &created, nil
+}
+
+// attachSubtask makes an issue a sub-issue of its parent, falling back to a task list item
+// in the parent's body on instances without sub-issues
+func (c *Client) attachSubtask(parent int, child *issue) error {
+	path := c.repoPath(fmt.Sprintf("issues/%d/sub_issues", parent))
+	err := c.api.Do(http.MethodPost, path, map[string]interface{}{"sub_issue_id": child.ID}, nil)
+	if err == nil {
+		return nil
+	}
+	var apiErr *tracker.APIError
+	if !errors.As(err, &apiErr) || (apiErr.StatusCode != http.StatusNotFound && apiErr.StatusCode != http.StatusUnprocessableEntity) {
+		return err
+	}
+
+	var p issue
+	if err := c.api.Do(http.MethodGet, c.repoPath(fmt.Sprintf("issues/%d", parent)), nil, &p); err != nil {
+		return err
+	}
+	body := strings.TrimRight(p.Body, "\n")
+	if body != "" {
+		body += "\n"
+	}
+	body += fmt.Sprintf("- [ ] #%d", child.Number)
+	return c.api.Do(http.MethodPatch, c.repoPath(fmt.Sprintf("issues/%d", parent)), map[string]interface{}{"body": body}, nil)
+}
+
+// GetTicket retrieves an issue or milestone by key
+func (c *Client) GetTicket(key string) (*types.Ticket, error) {
+	if number, ok := tracker.KeyNumber(key, MilestonePrefix); ok {
+		var m milestone
+		if err := c.api.Do(http.MethodGet, c.repoPath(fmt.Sprintf("milestones/%d", number)), nil, &m); err != nil {
+			return nil, fmt.Errorf("failed to get GitHub milestone %s: %w", key, err)
+		}
+		return convertMilestone(&m), nil
+	}
+
+	number, ok := tracker.KeyNumber(key, IssuePrefix)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a GitHub key (expected %s-<number> or %s-<number>)", key, IssuePrefix, MilestonePrefix)
+	}
+	var is issue
+	if err := c.api.Do(http.MethodGet, c.repoPath(fmt.Sprintf("issues/%d", number)), nil, &is); err != nil {
+		return nil, fmt.Errorf("failed to get GitHub issue %s: %w", key, err)
+	}
+	return convertIssue(&is), nil
+}
+
+// UpdateTicket updates the title, description and labels of an issue or milestone
+func (c *Client) UpdateTicket(ticket *types.Ticket) error {
+	if number, ok := tracker.KeyNumber(ticket.Key, MilestonePrefix); ok {
+		request := map[string]interface{}{"title": ticket.Title}
+		if desc := tracker.Description(ticket); desc != "" {
+			request["description"] = desc
+		}
+		if err := c.api.Do(http.MethodPatch, c.repoPath(fmt.Sprintf("milestones/%d", number)), request, nil); err != nil {
+			return fmt.Errorf("failed to update GitHub milestone %s: %w", ticket.Key, err)
+		}
+		return nil
+	}
+
+	number, ok := tracker.KeyNumber(ticket.Key, IssuePrefix)
+	if !ok {
+		return fmt.Errorf("%s is not a GitHub key", ticket.Key)
+	}
+	request := map[string]interface{}{"title": ticket.Title}
+	if desc := tracker.Description(ticket); desc != "" {
+		request["body"] = desc
+	}
+	if len(ticket.Labels) > 0 {
+		request["labels"] = ticket.Labels
+	}
+	if err := c.api.Do(http.MethodPatch, c.repoPath(fmt.Sprintf("issues/%d", number)), request, nil); err != nil {
+		return fmt.Errorf("failed to update GitHub issue %s: %w", ticket.Key, err)
+	}
+	return nil
+}
+
+// SearchTickets returns the repository's issues matching a GitHub search query, e.g.
+// "is:open label:bug"
+func (c *Client) SearchTickets(query string) ([]*types.Ticket, error) {
+	q := strings.TrimSpace(fmt.Sprintf("repo:%s is:issue %s", c.repo, query))
+
+	var tickets []*types.Ticket
+	for page := 1; ; page++ {
+		var result struct {
+			TotalCount int     `json:"total_count"`
+			Items      []issue `json:"items"`
+		}
+		path := fmt.Sprintf("/search/issues?q=%s&per_page=%d&page=%d", url.QueryEscape(q), searchPageSize, page)
+		if err := c.api.Do(http.MethodGet, path, nil, &result); err != nil {
+			return nil, fmt.Errorf("failed to search GitHub issues: %w", err)
+		}
+
+		for i := range result.Items {
+			tickets = append(tickets, convertIssue(&result.Items[i]))
+		}
+		if len(result.Items) < searchPageSize || len(tickets) >= result.TotalCount || len(tickets) >= maxSearchResults {
+			break
+		}
+	}
+	return tickets, nil
+}
+
+// Transition opens or closes an issue or milestone. GitHub only has the two states, so
+// statuses like Done or Closed close it and any other status keeps it open.
+func (c *Client) Transition(key, status string) (string, error) {
+	state := "open"
+	if tracker.IsClosedStatus(status) {
+		state = "closed"
+	}
+
+	path := ""
+	if number, ok := tracker.KeyNumber(key, MilestonePrefix); ok {
+		path = c.repoPath(fmt.Sprintf("milestones/%d", number))
+	} else if number, ok := tracker.KeyNumber(key, IssuePrefix); ok {
+		path = c.repoPath(fmt.Sprintf("issues/%d", number))
+	} else {
+		return "", fmt.Errorf("%s is not a GitHub key", key)
+	}
+
+	if err := c.api.Do(http.MethodPatch, path, map[string]interface{}{"state": state}, nil); err != nil {
+		return "", fmt.Errorf("failed to transition %s: %w", key, err)
+	}
+	return tracker.OpenClosedStatus(state), nil
+}
+
+// repoPath returns the API path of a resource in the configured repository
+func (c *Client) repoPath(resource string) string {
+	return fmt.Sprintf("/repos/%s/%s", c.repo, resource)
+}
+
+// convertIssue converts a GitHub issue to our Ticket type
+func convertIssue(is *issue) *types.Ticket {
+	ticket := &types.Ticket{
+		Key:         fmt.Sprintf("%s-%d", IssuePrefix, is.Number),
+		ID:          strconv.FormatInt(is.ID, 10),
+		Type:        types.TicketTypeTask,
+		Title:       is.Title,
+		Description: is.Body,
+		Status:      tracker.OpenClosedStatus(is.State),
+		Created:     is.CreatedAt,
+		Updated:     is.UpdatedAt,
+	}
+	for _, l := range is.Labels {
+		ticket.Labels = append(ticket.Labels, l.Name)
+	}
+	if len(is.Assignees) > 0 {
+		ticket.Assignee = is.Assignees[0].Login
+	}
+	if is.Milestone != nil {
+		ticket.EpicKey = fmt.Sprintf("%s-%d", MilestonePrefix, is.Milestone.Number)
+	}
+	return ticket
+}
+
+// convertMilestone converts a GitHub milestone to an epic
+func convertMilestone(m *milestone) *types.Ticket {
+	ticket := &types.Ticket{
+		Key:         fmt.Sprintf("%s-%d", MilestonePrefix, m.Number),
+		ID:          strconv.Itoa(m.Number),
+		Type:        types.TicketTypeEpic,
+		Title:       m.Title,
+		Description: m.Description,
+		Status:      tracker.OpenClosedStatus(m.State),
+		Created:     m.CreatedAt,
+		Updated:     m.UpdatedAt,
+	}
+	if m.DueOn != nil {
+		due := *m.DueOn
+		ticket.DueDate = &due
+	}
+	return ticket
+}
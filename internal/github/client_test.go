@@ -0,0 +1,286 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lunchboxsushi/jai/internal/tracker"
+	"github.com/lunchboxsushi/jai/internal/tracker/trackertest"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// newTestClient starts a recording server that answers every request with handler, and
+// returns a client for the acme/app repository on it
+func newTestClient(t *testing.T, handler func(w http.ResponseWriter, r trackertest.Request)) (*Client, *[]trackertest.Request) {
+	t.Helper()
+	serverURL, requests := trackertest.NewServer(t, func(w http.ResponseWriter, r trackertest.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("%s %s: Authorization = %q", r.Method, r.Path, got)
+		}
+		handler(w, r)
+	})
+
+	config := &types.Config{}
+	config.Tracker.GitHub.URL = serverURL
+	config.Tracker.GitHub.Repo = "acme/app"
+	config.Tracker.GitHub.Token = "secret"
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, requests
+}
+
+func TestCreateMilestone(t *testing.T) {
+	client, requests := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+		trackertest.Reply(w, http.StatusCreated, `{"number": 3, "title": "Tracing", "state": "open"}`)
+	})
+
+	due := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	created, err := client.CreateTicket(&types.Ticket{
+		Type:       types.TicketTypeEpic,
+		Title:      "Tracing",
+		RawContent: "  Roll out tracing  ",
+		DueDate:    &due,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Key != "GHM-3" || created.ID != "3" || created.Status != tracker.StatusOpen {
+		t.Errorf("created %s (ID %s, %s), want GHM-3 (ID 3, %s)", created.Key, created.ID, created.Status, tracker.StatusOpen)
+	}
+
+	if len(*requests) != 1 {
+		t.Fatalf("sent %d requests, want 1", len(*requests))
+	}
+	r := (*requests)[0]
+	if r.Method != http.MethodPost || r.Path != "/repos/acme/app/milestones" {
+		t.Errorf("sent %s %s, want POST /repos/acme/app/milestones", r.Method, r.Path)
+	}
+	if r.Body["title"] != "Tracing" || r.Body["description"] != "Roll out tracing" || r.Body["due_on"] != "2024-06-30T00:00:00Z" {
+		t.Errorf("milestone request = %v", r.Body)
+	}
+}
+
+func TestCreateIssueInMilestone(t *testing.T) {
+	client, requests := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+		trackertest.Reply(w, http.StatusCreated, `{"id": 9001, "number": 12, "title": "Add spans", "state": "open"}`)
+	})
+
+	created, err := client.CreateTicket(&types.Ticket{
+		Type:        types.TicketTypeTask,
+		Title:       "Add spans",
+		Description: "Instrument the API",
+		EpicKey:     "GHM-3",
+		Labels:      []string{"tracing"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Key != "GH-12" || created.ID != "9001" || created.EpicKey != "GHM-3" {
+		t.Errorf("created %s (ID %s, epic %s), want GH-12 (ID 9001, epic GHM-3)", created.Key, created.ID, created.EpicKey)
+	}
+
+	if len(*requests) != 1 {
+		t.Fatalf("sent %d requests, want 1", len(*requests))
+	}
+	r := (*requests)[0]
+	if r.Method != http.MethodPost || r.Path != "/repos/acme/app/issues" {
+		t.Errorf("sent %s %s, want POST /repos/acme/app/issues", r.Method, r.Path)
+	}
+	// JSON numbers decode as float64
+	if r.Body["milestone"] != float64(3) || r.Body["body"] != "Instrument the API" {
+		t.Errorf("issue request = %v, want milestone 3 and the description as body", r.Body)
+	}
+	if labels, _ := r.Body["labels"].([]interface{}); len(labels) != 1 || labels[0] != "tracing" {
+		t.Errorf("issue labels = %v, want [tracing]", r.Body["labels"])
+	}
+}
+
+func TestCreateSubtaskAsSubIssue(t *testing.T) {
+	client, requests := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+		switch r.Path {
+		case "/repos/acme/app/issues":
+			trackertest.Reply(w, http.StatusCreated, `{"id": 9002, "number": 13, "state": "open"}`)
+		case "/repos/acme/app/issues/12/sub_issues":
+			trackertest.Reply(w, http.StatusCreated, `{"id": 9001, "number": 12}`)
+		default:
+			trackertest.Reply(w, http.StatusNotFound, `{"message": "Not Found"}`)
+		}
+	})
+
+	created, err := client.CreateTicket(&types.Ticket{Type: types.TicketTypeSubtask, Title: "Trace the DB", ParentKey: "GH-12"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Key != "GH-13" {
+		t.Errorf("created %s, want GH-13", created.Key)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("sent %d requests, want the create and the sub-issue link", len(*requests))
+	}
+	r := (*requests)[1]
+	if r.Method != http.MethodPost || r.Body["sub_issue_id"] != float64(9002) {
+		t.Errorf("sub-issue request = %s %v, want POST with sub_issue_id 9002", r.Method, r.Body)
+	}
+}
+
+func TestCreateSubtaskTaskListFallback(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusUnprocessableEntity} {
+		t.Run(strconv.Itoa(status), func(t *testing.T) {
+			client, requests := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+				switch {
+				case r.Path == "/repos/acme/app/issues":
+					trackertest.Reply(w, http.StatusCreated, `{"id": 9002, "number": 13, "state": "open"}`)
+				case strings.HasSuffix(r.Path, "/sub_issues"):
+					trackertest.Reply(w, status, `{"message": "Sub-issues are not available"}`)
+				case r.Method == http.MethodGet:
+					trackertest.Reply(w, http.StatusOK, `{"id": 9001, "number": 12, "body": "Plan:\n"}`)
+				default:
+					trackertest.Reply(w, http.StatusOK, `{}`)
+				}
+			})
+
+			if _, err := client.CreateTicket(&types.Ticket{Type: types.TicketTypeSubtask, Title: "Trace the DB", ParentKey: "GH-12"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(*requests) != 4 {
+				t.Fatalf("sent %d requests, want create, sub-issue, get parent and patch parent", len(*requests))
+			}
+			patch := (*requests)[3]
+			if patch.Method != http.MethodPatch || patch.Path != "/repos/acme/app/issues/12" {
+				t.Errorf("sent %s %s, want PATCH /repos/acme/app/issues/12", patch.Method, patch.Path)
+			}
+			if patch.Body["body"] != "Plan:\n- [ ] #13" {
+				t.Errorf("parent body = %q, want the subtask added as a task list item", patch.Body["body"])
+			}
+		})
+	}
+}
+
+func TestCreateSubtaskOtherErrorsDontFallBack(t *testing.T) {
+	client, requests := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+		if r.Path == "/repos/acme/app/issues" {
+			trackertest.Reply(w, http.StatusCreated, `{"id": 9002, "number": 13, "state": "open"}`)
+			return
+		}
+		trackertest.Reply(w, http.StatusForbidden, `{"message": "Forbidden"}`)
+	})
+
+	// The issue exists, so a failed link is only logged
+	created, err := client.CreateTicket(&types.Ticket{Type: types.TicketTypeSubtask, Title: "Trace the DB", ParentKey: "GH-12"})
+	if err != nil || created.Key != "GH-13" {
+		t.Fatalf("CreateTicket = %v, %v; want GH-13", created, err)
+	}
+	if len(*requests) != 2 {
+		t.Errorf("sent %d requests, want no task list fallback after a 403", len(*requests))
+	}
+}
+
+func TestSearchTicketsPagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		totalCount int
+		wantPages  int
+		wantIssues int
+	}{
+		{"stops at total_count", 150, 2, 150},
+		{"stops at a full last page", 200, 2, 200},
+		{"stops at GitHub's 1000 result limit", 5000, 10, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+				page, err := strconv.Atoi(r.Query.Get("page"))
+				if err != nil {
+					t.Errorf("search without a page: %q", r.Query.Encode())
+				}
+				count := min(searchPageSize, tt.totalCount-(page-1)*searchPageSize)
+				var items []string
+				for i := 0; i < count; i++ {
+					number := (page-1)*searchPageSize + i + 1
+					items = append(items, fmt.Sprintf(`{"id": %d, "number": %d, "state": "open"}`, number, number))
+				}
+				trackertest.Reply(w, http.StatusOK, fmt.Sprintf(`{"total_count": %d, "items": [%s]}`, tt.totalCount, strings.Join(items, ",")))
+			})
+
+			tickets, err := client.SearchTickets("is:open")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(*requests) != tt.wantPages || len(tickets) != tt.wantIssues {
+				t.Errorf("fetched %d pages with %d issues, want %d pages with %d", len(*requests), len(tickets), tt.wantPages, tt.wantIssues)
+			}
+			first := (*requests)[0].Query
+			if first.Get("q") != "repo:acme/app is:issue is:open" || first.Get("per_page") != "100" {
+				t.Errorf("search query = %v", first)
+			}
+		})
+	}
+}
+
+func TestTransition(t *testing.T) {
+	tests := []struct {
+		key        string
+		status     string
+		wantPath   string
+		wantState  string
+		wantStatus string
+	}{
+		{"GH-12", "Done", "/repos/acme/app/issues/12", "closed", tracker.StatusClosed},
+		{"gh-12", "closed", "/repos/acme/app/issues/12", "closed", tracker.StatusClosed},
+		{"GH-12", "In Progress", "/repos/acme/app/issues/12", "open", tracker.StatusOpen},
+		{"GHM-3", "Resolved", "/repos/acme/app/milestones/3", "closed", tracker.StatusClosed},
+		{"GHM-3", "Reopened", "/repos/acme/app/milestones/3", "open", tracker.StatusOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+" "+tt.status, func(t *testing.T) {
+			client, requests := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+				trackertest.Reply(w, http.StatusOK, `{}`)
+			})
+
+			status, err := client.Transition(tt.key, tt.status)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("Transition returned %q, want %q", status, tt.wantStatus)
+			}
+			if len(*requests) != 1 {
+				t.Fatalf("sent %d requests, want 1", len(*requests))
+			}
+			r := (*requests)[0]
+			if r.Method != http.MethodPatch || r.Path != tt.wantPath || r.Body["state"] != tt.wantState {
+				t.Errorf("sent %s %s %v, want PATCH %s with state %s", r.Method, r.Path, r.Body, tt.wantPath, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestTransitionRejectsOtherKeys(t *testing.T) {
+	client, requests := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+		trackertest.Reply(w, http.StatusOK, `{}`)
+	})
+	if _, err := client.Transition("OBS-12", "Done"); err == nil {
+		t.Error("Transition of a Jira key succeeded")
+	}
+	if len(*requests) != 0 {
+		t.Errorf("sent %d requests for a key that isn't GitHub's", len(*requests))
+	}
+}
+
+func TestTransitionAPIError(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+		trackertest.Reply(w, http.StatusNotFound, `{"message": "Not Found"}`)
+	})
+	_, err := client.Transition("GH-404", "Done")
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Transition of a missing issue = %v, want a 404 error", err)
+	}
+}
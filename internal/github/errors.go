@@ -0,0 +1,32 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// errorDetails reads the message and per-field errors of a GitHub error response
+func errorDetails(data []byte) (string, []string) {
+	var body struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Field   string `json:"field"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(data, &body) != nil {
+		return string(data), nil
+	}
+
+	var errs []string
+	for _, e := range body.Errors {
+		switch {
+		case e.Message != "":
+			errs = append(errs, e.Message)
+		case e.Field != "":
+			errs = append(errs, fmt.Sprintf("%s %s", e.Field, e.Code))
+		}
+	}
+	return body.Message, errs
+}
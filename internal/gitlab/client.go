@@ -0,0 +1,414 @@
+package gitlab
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jai/internal/tracker"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// DefaultURL is the GitLab instance used when tracker.gitlab.url is not set
+const DefaultURL = "https://gitlab.com"
+
+// Key prefixes: issues and tasks are GL-<iid> and group epics GLE-<iid>
+const (
+	IssuePrefix = "GL"
+	EpicPrefix  = "GLE"
+)
+
+// Work item types of project issues
+const (
+	issueTypeIssue = "issue"
+	issueTypeTask  = "task"
+)
+
+const (
+	// searchPageSize is the number of issues requested per page, GitLab's maximum
+	searchPageSize = 100
+
+	// maxSearchResults caps the issues returned by a single search
+	maxSearchResults = 1000
+)
+
+var _ tracker.Backend = (*Client)(nil)
+
+// Client handles GitLab API interactions. Epics are group epics, tasks are issues in the
+// project assigned to the epic, and subtasks are GitLab tasks nested under their issue.
+// Most calls use the REST API; the task hierarchy is only available through GraphQL.
+type Client struct {
+	api     *tracker.APIClient // REST API, e.g. https://gitlab.com/api/v4
+	graphql *tracker.APIClient // GraphQL API, e.g. https://gitlab.com/api/graphql
+	project string             // Path or ID
+	group   string             // Path or ID, only needed for epics
+}
+
+// NewClient creates a new GitLab client for the configured project
+func NewClient(config *types.Config) (*Client, error) {
+	project := strings.Trim(config.Tracker.GitLab.Project, "/")
+	if project == "" {
+		return nil, fmt.Errorf("tracker.gitlab.project must be set to a project path (group/name) or ID")
+	}
+
+	baseURL := config.Tracker.GitLab.URL
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	root := strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/api/v4")
+
+	restHeader, graphqlHeader := http.Header{}, http.Header{}
+	if token := config.Tracker.GitLab.Token; token != "" {
+		restHeader.Set("PRIVATE-TOKEN", token)
+		graphqlHeader.Set("Authorization", "Bearer "+token)
+	}
+
+	return &Client{
+		api:     tracker.NewAPIClient("GitLab", root+"/api/v4", restHeader, errorDetails),
+		graphql: tracker.NewAPIClient("GitLab", root+"/api/graphql", graphqlHeader, tracker.GraphQLErrorDetails),
+		project: project,
+		group:   strings.Trim(config.Tracker.GitLab.Group, "/"),
+	}, nil
+}
+
+// issue is a GitLab issue or task as returned by the REST API
+type issue struct {
+	ID          int64     `json:"id"`
+	IID         int       `json:"iid"`
+	ProjectID   int64     `json:"project_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	State       string    `json:"state"`
+	IssueType   string    `json:"issue_type"`
+	Labels      []string  `json:"labels"`
+	Assignees   []user    `json:"assignees"`
+	EpicIID     *int      `json:"epic_iid"`
+	DueDate     string    `json:"due_date"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// epic is a GitLab group epic as returned by the REST API
+type epic struct {
+	ID          int64     `json:"id"`
+	IID         int       `json:"iid"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	State       string    `json:"state"`
+	Labels      []string  `json:"labels"`
+	DueDate     string    `json:"due_date"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type user struct {
+	Username string `json:"username"`
+}
+
+// CreateTicket creates a group epic for an epic, an issue for a task or a task for a
+// subtask. Issues are added to their epic and tasks nested under their issue.
+func (c *Client) CreateTicket(ticket *types.Ticket) (*types.Ticket, error) {
+	created := *ticket
+
+	if ticket.Type == types.TicketTypeEpic {
+		if c.group == "" {
+			return nil, fmt.Errorf("tracker.gitlab.group must be set to create epics")
+		}
+		request := map[string]interface{}{
+			"title":       ticket.Title,
+			"description": tracker.Description(ticket),
+		}
+		if len(ticket.Labels) > 0 {
+			request["labels"] = strings.Join(ticket.Labels, ",")
+		}
+		if ticket.DueDate != nil {
+			request["due_date_is_fixed"] = true
+			request["due_date_fixed"] = ticket.DueDate.Format(tracker.DateFormat)
+		}
+
+		var e epic
+		if err := c.api.Do(http.MethodPost, c.groupPath("epics"), request, &e); err != nil {
+			return nil, fmt.Errorf("failed to create GitLab epic: %w", err)
+		}
+		created.Key = fmt.Sprintf("%s-%d", EpicPrefix, e.IID)
+		created.ID = strconv.FormatInt(e.ID, 10)
+		created.Status = tracker.OpenClosedStatus(e.State)
+		created.Created = time.Now()
+		created.Updated = time.Now()
+		return &created, nil
+	}
+
+	issueType := issueTypeIssue
+	if ticket.Type == types.TicketTypeSubtask {
+		issueType = issueTypeTask
+	}
+	request := map[string]interface{}{
+		"title":       ticket.Title,
+		"description": tracker.Description(ticket),
+		"issue_type":  issueType,
+	}
+	if len(ticket.Labels) > 0 {
+		request["labels"] = strings.Join(ticket.Labels, ",")
+	}
+	if ticket.DueDate != nil {
+		request["due_date"] = ticket.DueDate.Format(tracker.DateFormat)
+	}
+
+	var is issue
+	if err := c.api.Do(http.MethodPost, c.projectPath("issues"), request, &is); err != nil {
+		return nil, fmt.Errorf("failed to create GitLab %s: %w", issueType, err)
+	}
+	created.Key = fmt.Sprintf("%s-%d", IssuePrefix, is.IID)
+	created.ID = strconv.FormatInt(is.ID, 10)
+	created.Status = tracker.OpenClosedStatus(is.State)
+	created.Created = time.Now()
+	created.Updated = time.Now()
+
+	if ticket.Type == types.TicketTypeSubtask {
+		if parent, ok := tracker.KeyNumber(ticket.ParentKey, IssuePrefix); ok {
+			if err := c.setParent(parent, &is); err != nil {
+				log.Printf("Warning: Created %s but failed to nest it under %s: %v", created.Key, ticket.ParentKey, err)
+			}
+		}
+	} else if epicIID, ok := tracker.KeyNumber(ticket.EpicKey, EpicPrefix); ok {
+		if err := c.assignEpic(epicIID, &is); err != nil {
+			log.Printf("Warning: Created %s but failed to add it to %s: %v", created.Key, ticket.EpicKey, err)
+		}
+	}
+
+	return &created, nil
+}
+
+// assignEpic adds an issue to a group epic
+func (c *Client) assignEpic(epicIID int, is *issue) error {
+	if c.group == "" {
+		return fmt.Errorf("tracker.gitlab.group is not set")
+	}
+	return c.api.Do(http.MethodPost, c.groupPath(fmt.Sprintf("epics/%d/issues/%d", epicIID, is.ID)), nil, nil)
+}
+
+// setParent nests a task under the issue it belongs to, through the work item hierarchy
+func (c *Client) setParent(parentIID int, task *issue) error {
+	var parent issue
+	if err := c.api.Do(http.MethodGet, c.projectPath(fmt.Sprintf("issues/%d", parentIID)), nil, &parent); err != nil {
+		return err
+	}
+
+	var result struct {
+		WorkItemUpdate struct {
+			Errors []string `json:"errors"`
+		} `json:"workItemUpdate"`
+	}
+	input := map[string]interface{}{
+		"id":              workItemID(task.ID),
+		"hierarchyWidget": map[string]interface{}{"parentId": workItemID(parent.ID)},
+	}
+	query := `mutation($input: WorkItemUpdateInput!) { workItemUpdate(input: $input) { errors } }`
+	if err := c.graphql.GraphQL(query, map[string]interface{}{"input": input}, &result); err != nil {
+		return err
+	}
+	if len(result.WorkItemUpdate.Errors) > 0 {
+		return &tracker.APIError{Tracker: "GitLab", StatusCode: http.StatusOK, Errors: result.WorkItemUpdate.Errors}
+	}
+	return nil
+}
+
+// taskParent returns the key of the issue a task is nested under, or "" if it has none
+func (c *Client) taskParent(task *issue) (string, error) {
+	var result struct {
+		WorkItem *struct {
+			Widgets []struct {
+				Parent *struct {
+					IID string `json:"iid"`
+				} `json:"parent"`
+			} `json:"widgets"`
+		} `json:"workItem"`
+	}
+	query := `query($id: WorkItemID!) { workItem(id: $id) { widgets { ... on WorkItemWidgetHierarchy { parent { iid } } } } }`
+	if err := c.graphql.GraphQL(query, map[string]interface{}{"id": workItemID(task.ID)}, &result); err != nil {
+		return "", err
+	}
+	if result.WorkItem == nil {
+		return "", nil
+	}
+	for _, widget := range result.WorkItem.Widgets {
+		if widget.Parent != nil {
+			return fmt.Sprintf("%s-%s", IssuePrefix, widget.Parent.IID), nil
+		}
+	}
+	return "", nil
+}
+
+// workItemID returns the GraphQL ID of the work item behind an issue or task
+func workItemID(id int64) string {
+	return fmt.Sprintf("gid://gitlab/WorkItem/%d", id)
+}
+
+// GetTicket retrieves an issue, task or epic by key. A task's parent issue is looked up
+// through the work item hierarchy.
+func (c *Client) GetTicket(key string) (*types.Ticket, error) {
+	if iid, ok := tracker.KeyNumber(key, EpicPrefix); ok {
+		if c.group == "" {
+			return nil, fmt.Errorf("tracker.gitlab.group must be set to read epics")
+		}
+		var e epic
+		if err := c.api.Do(http.MethodGet, c.groupPath(fmt.Sprintf("epics/%d", iid)), nil, &e); err != nil {
+			return nil, fmt.Errorf("failed to get GitLab epic %s: %w", key, err)
+		}
+		return convertEpic(&e), nil
+	}
+
+	iid, ok := tracker.KeyNumber(key, IssuePrefix)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a GitLab key (expected %s-<iid> or %s-<iid>)", key, IssuePrefix, EpicPrefix)
+	}
+	var is issue
+	if err := c.api.Do(http.MethodGet, c.projectPath(fmt.Sprintf("issues/%d", iid)), nil, &is); err != nil {
+		return nil, fmt.Errorf("failed to get GitLab issue %s: %w", key, err)
+	}
+	ticket := convertIssue(&is)
+	if ticket.Type == types.TicketTypeSubtask {
+		parent, err := c.taskParent(&is)
+		if err != nil {
+			log.Printf("Warning: Failed to find the issue %s belongs to: %v", ticket.Key, err)
+		}
+		ticket.ParentKey = parent
+	}
+	return ticket, nil
+}
+
+// UpdateTicket updates the title, description and labels of an issue, task or epic
+func (c *Client) UpdateTicket(ticket *types.Ticket) error {
+	request := map[string]interface{}{"title": ticket.Title}
+	if desc := tracker.Description(ticket); desc != "" {
+		request["description"] = desc
+	}
+	if len(ticket.Labels) > 0 {
+		request["labels"] = strings.Join(ticket.Labels, ",")
+	}
+
+	path, err := c.ticketPath(ticket.Key)
+	if err != nil {
+		return err
+	}
+	if err := c.api.Do(http.MethodPut, path, request, nil); err != nil {
+		return fmt.Errorf("failed to update GitLab ticket %s: %w", ticket.Key, err)
+	}
+	return nil
+}
+
+// SearchTickets returns the project's issues and tasks whose title or description match the
+// query. Finding a task's parent takes a GraphQL query per task, so only GetTicket sets it.
+func (c *Client) SearchTickets(query string) ([]*types.Ticket, error) {
+	var tickets []*types.Ticket
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("per_page", strconv.Itoa(searchPageSize))
+		params.Set("page", strconv.Itoa(page))
+		if query = strings.TrimSpace(query); query != "" {
+			params.Set("search", query)
+		}
+
+		var issues []issue
+		if err := c.api.Do(http.MethodGet, c.projectPath("issues?"+params.Encode()), nil, &issues); err != nil {
+			return nil, fmt.Errorf("failed to search GitLab issues: %w", err)
+		}
+
+		for i := range issues {
+			tickets = append(tickets, convertIssue(&issues[i]))
+		}
+		if len(issues) < searchPageSize || len(tickets) >= maxSearchResults {
+			break
+		}
+	}
+	return tickets, nil
+}
+
+// Transition closes or reopens an issue, task or epic. GitLab only has the two states, so
+// statuses like Done or Closed close it and any other status reopens it.
+func (c *Client) Transition(key, status string) (string, error) {
+	event, state := "reopen", "opened"
+	if tracker.IsClosedStatus(status) {
+		event, state = "close", "closed"
+	}
+
+	path, err := c.ticketPath(key)
+	if err != nil {
+		return "", err
+	}
+	if err := c.api.Do(http.MethodPut, path, map[string]interface{}{"state_event": event}, nil); err != nil {
+		return "", fmt.Errorf("failed to transition %s: %w", key, err)
+	}
+	return tracker.OpenClosedStatus(state), nil
+}
+
+// ticketPath returns the API path of the issue or epic a key refers to
+func (c *Client) ticketPath(key string) (string, error) {
+	if iid, ok := tracker.KeyNumber(key, EpicPrefix); ok {
+		if c.group == "" {
+			return "", fmt.Errorf("tracker.gitlab.group must be set to update epics")
+		}
+		return c.groupPath(fmt.Sprintf("epics/%d", iid)), nil
+	}
+	if iid, ok := tracker.KeyNumber(key, IssuePrefix); ok {
+		return c.projectPath(fmt.Sprintf("issues/%d", iid)), nil
+	}
+	return "", fmt.Errorf("%s is not a GitLab key", key)
+}
+
+// projectPath returns the API path of a resource in the configured project
+func (c *Client) projectPath(resource string) string {
+	return fmt.Sprintf("/projects/%s/%s", url.PathEscape(c.project), resource)
+}
+
+// groupPath returns the API path of a resource in the configured group
+func (c *Client) groupPath(resource string) string {
+	return fmt.Sprintf("/groups/%s/%s", url.PathEscape(c.group), resource)
+}
+
+// convertIssue converts a GitLab issue or task to our Ticket type. Tasks become subtasks
+// without a ParentKey, as the REST API doesn't say which issue they are nested under.
+func convertIssue(is *issue) *types.Ticket {
+	ticket := &types.Ticket{
+		Key:         fmt.Sprintf("%s-%d", IssuePrefix, is.IID),
+		ID:          strconv.FormatInt(is.ID, 10),
+		Type:        types.TicketTypeTask,
+		Title:       is.Title,
+		Description: is.Description,
+		Status:      tracker.OpenClosedStatus(is.State),
+		Labels:      is.Labels,
+		DueDate:     tracker.ParseDate(is.DueDate),
+		Created:     is.CreatedAt,
+		Updated:     is.UpdatedAt,
+	}
+	if is.IssueType == issueTypeTask {
+		ticket.Type = types.TicketTypeSubtask
+	}
+	if len(is.Assignees) > 0 {
+		ticket.Assignee = is.Assignees[0].Username
+	}
+	if is.EpicIID != nil {
+		ticket.EpicKey = fmt.Sprintf("%s-%d", EpicPrefix, *is.EpicIID)
+	}
+	return ticket
+}
+
+// convertEpic converts a GitLab epic to our Ticket type
+func convertEpic(e *epic) *types.Ticket {
+	return &types.Ticket{
+		Key:         fmt.Sprintf("%s-%d", EpicPrefix, e.IID),
+		ID:          strconv.FormatInt(e.ID, 10),
+		Type:        types.TicketTypeEpic,
+		Title:       e.Title,
+		Description: e.Description,
+		Status:      tracker.OpenClosedStatus(e.State),
+		Labels:      e.Labels,
+		DueDate:     tracker.ParseDate(e.DueDate),
+		Created:     e.CreatedAt,
+		Updated:     e.UpdatedAt,
+	}
+}
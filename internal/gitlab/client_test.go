@@ -0,0 +1,138 @@
+package gitlab
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jai/internal/tracker/trackertest"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// newTestClient starts a recording server that answers every request with handler, and
+// returns a client for the acme/app project on it
+func newTestClient(t *testing.T, handler func(w http.ResponseWriter, r trackertest.Request)) (*Client, *[]trackertest.Request) {
+	t.Helper()
+	serverURL, requests := trackertest.NewServer(t, func(w http.ResponseWriter, r trackertest.Request) {
+		if r.Path == "/api/graphql" {
+			if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+				t.Errorf("%s %s: Authorization = %q", r.Method, r.Path, got)
+			}
+		} else if got := r.Header.Get("PRIVATE-TOKEN"); got != "secret" {
+			t.Errorf("%s %s: PRIVATE-TOKEN = %q", r.Method, r.Path, got)
+		}
+		handler(w, r)
+	})
+
+	config := &types.Config{}
+	config.Tracker.GitLab.URL = serverURL
+	config.Tracker.GitLab.Project = "acme/app"
+	config.Tracker.GitLab.Token = "secret"
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, requests
+}
+
+func TestCreateSubtaskNestsUnderIssue(t *testing.T) {
+	client, requests := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.Path == "/api/v4/projects/acme%2Fapp/issues":
+			trackertest.Reply(w, http.StatusCreated, `{"id": 502, "iid": 8, "title": "Sample traces", "state": "opened", "issue_type": "task"}`)
+		case r.Method == http.MethodGet && r.Path == "/api/v4/projects/acme%2Fapp/issues/5":
+			trackertest.Reply(w, http.StatusOK, `{"id": 500, "iid": 5, "title": "Add spans", "state": "opened", "issue_type": "issue"}`)
+		case r.Path == "/api/graphql":
+			trackertest.Reply(w, http.StatusOK, `{"data": {"workItemUpdate": {"errors": []}}}`)
+		default:
+			trackertest.Reply(w, http.StatusNotFound, `{"message": "404 Not Found"}`)
+		}
+	})
+
+	created, err := client.CreateTicket(&types.Ticket{Type: types.TicketTypeSubtask, Title: "Sample traces", ParentKey: "GL-5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Key != "GL-8" {
+		t.Errorf("key = %s, want GL-8", created.Key)
+	}
+	if got := (*requests)[0].Body["issue_type"]; got != issueTypeTask {
+		t.Errorf("issue_type = %v, want %s", got, issueTypeTask)
+	}
+
+	last := (*requests)[len(*requests)-1]
+	query, variables := last.GraphQL()
+	if !strings.Contains(query, "workItemUpdate") {
+		t.Fatalf("last request = %s %s, want the workItemUpdate mutation", last.Method, last.Path)
+	}
+	input, _ := variables["input"].(map[string]interface{})
+	hierarchy, _ := input["hierarchyWidget"].(map[string]interface{})
+	if input["id"] != "gid://gitlab/WorkItem/502" || hierarchy["parentId"] != "gid://gitlab/WorkItem/500" {
+		t.Errorf("mutation input = %v, want task 502 nested under 500", input)
+	}
+	for _, r := range *requests {
+		if strings.HasSuffix(r.Path, "/links") {
+			t.Errorf("subtask was linked with %s %s instead of nested", r.Method, r.Path)
+		}
+	}
+}
+
+func TestSetParentReportsMutationErrors(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+		if r.Path == "/api/graphql" {
+			trackertest.Reply(w, http.StatusOK, `{"data": {"workItemUpdate": {"errors": ["Parent is not a valid parent"]}}}`)
+			return
+		}
+		trackertest.Reply(w, http.StatusOK, `{"id": 500, "iid": 5, "state": "opened"}`)
+	})
+
+	err := client.setParent(5, &issue{ID: 502, IID: 8})
+	if err == nil || !strings.Contains(err.Error(), "Parent is not a valid parent") {
+		t.Errorf("setParent error = %v, want the mutation's error", err)
+	}
+}
+
+func TestGetTaskReadsParent(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+		switch r.Path {
+		case "/api/v4/projects/acme%2Fapp/issues/8":
+			trackertest.Reply(w, http.StatusOK, `{"id": 502, "iid": 8, "title": "Sample traces", "state": "closed", "issue_type": "task"}`)
+		case "/api/graphql":
+			trackertest.Reply(w, http.StatusOK, `{"data": {"workItem": {"widgets": [{}, {"parent": {"iid": "5"}}]}}}`)
+		default:
+			trackertest.Reply(w, http.StatusNotFound, `{"message": "404 Not Found"}`)
+		}
+	})
+
+	ticket, err := client.GetTicket("GL-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticket.Type != types.TicketTypeSubtask || ticket.ParentKey != "GL-5" {
+		t.Errorf("GetTicket = %s with parent %q, want a subtask of GL-5", ticket.Type, ticket.ParentKey)
+	}
+}
+
+func TestSearchTicketsSkipsParentLookups(t *testing.T) {
+	client, requests := newTestClient(t, func(w http.ResponseWriter, r trackertest.Request) {
+		trackertest.Reply(w, http.StatusOK, `[
+			{"id": 500, "iid": 5, "title": "Add spans", "state": "opened", "issue_type": "issue"},
+			{"id": 502, "iid": 8, "title": "Sample traces", "state": "opened", "issue_type": "task"},
+			{"id": 503, "iid": 9, "title": "Trace sampling docs", "state": "closed", "issue_type": "task"}
+		]`)
+	})
+
+	tickets, err := client.SearchTickets("trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tickets) != 3 || tickets[1].Type != types.TicketTypeSubtask || tickets[2].Type != types.TicketTypeSubtask {
+		t.Fatalf("SearchTickets = %+v, want an issue and two subtasks", tickets)
+	}
+	if len(*requests) != 1 {
+		t.Errorf("sent %d requests, want only the issue search", len(*requests))
+	}
+	if got := (*requests)[0].Query.Get("search"); got != "trace" {
+		t.Errorf("search = %q, want trace", got)
+	}
+}
@@ -0,0 +1,40 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// errorDetails reads the message and per-field errors of a GitLab error response. GitLab
+// reports errors as {"message": "..."}, {"message": {"field": ["..."]}} or {"error": "..."}.
+func errorDetails(data []byte) (string, []string) {
+	var body struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+	}
+	if json.Unmarshal(data, &body) != nil {
+		return string(data), nil
+	}
+
+	var message string
+	var fields map[string][]string
+	switch {
+	case json.Unmarshal(body.Message, &message) == nil:
+		return message, nil
+	case json.Unmarshal(body.Message, &fields) == nil:
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var errs []string
+		for _, name := range names {
+			for _, problem := range fields[name] {
+				errs = append(errs, fmt.Sprintf("%s %s", name, problem))
+			}
+		}
+		return body.Error, errs
+	}
+	return body.Error, nil
+}
@@ -0,0 +1,116 @@
+package jira
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/lunchboxsushi/jai/internal/convert"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// UploadAttachment attaches a local file to a ticket. The returned attachment records
+// the file under the link target it was referenced by.
+func (c *Client) UploadAttachment(key, target, filePath string) (*types.Attachment, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+	defer file.Close()
+
+	attachments, resp, err := c.client.Issue.PostAttachment(key, file, filepath.Base(filePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach %s to %s: %w", filepath.Base(filePath), key, newAPIError(resp, err))
+	}
+	defer resp.Body.Close()
+
+	if attachments == nil || len(*attachments) == 0 {
+		return nil, fmt.Errorf("failed to attach %s to %s: Jira returned no attachment", filepath.Base(filePath), key)
+	}
+	return &types.Attachment{ID: (*attachments)[0].ID, Path: target}, nil
+}
+
+// jiraDescription returns a ticket's description with links to uploaded local files
+// pointing at their Jira attachments instead
+func (c *Client) jiraDescription(ticket *types.Ticket) string {
+	if len(ticket.Attachments) == 0 {
+		return ticket.Description
+	}
+
+	return convert.RewriteLinks(ticket.Description, func(target string, image bool) string {
+		attachment := findAttachment(ticket.Attachments, target)
+		if attachment == nil {
+			return ""
+		}
+		name := path.Base(attachment.Path)
+		switch {
+		case c.useADF():
+			// ADF can only embed media uploaded through the media API, so link to the file
+			site := c.SiteURL()
+			if site == "" {
+				return ""
+			}
+			return fmt.Sprintf("%s/secure/attachment/%s/%s", site, attachment.ID, name)
+		case image:
+			// Renders as !name!, which Jira resolves against the issue's attachments
+			return name
+		default:
+			return "^" + name
+		}
+	})
+}
+
+// SiteURL returns the URL people open the Jira site at. OAuth clients talk to the Atlassian
+// API gateway instead, so without jira.url the site's base URL is asked from Jira.
+func (c *Client) SiteURL() string {
+	if c.siteResolved {
+		return c.siteURL
+	}
+	c.siteResolved = true
+
+	if c.config.Jira.URL != "" {
+		c.siteURL = strings.TrimRight(c.config.Jira.URL, "/")
+		return c.siteURL
+	}
+
+	var info struct {
+		BaseURL string `json:"baseUrl"`
+	}
+	if resp, err := c.getJSON("rest/api/2/serverInfo", &info); err != nil {
+		log.Printf("Warning: Failed to look up the Jira site URL, attachment links keep their local paths (set jira.url): %v", newAPIError(resp, err))
+		return ""
+	}
+	c.siteURL = strings.TrimRight(info.BaseURL, "/")
+	return c.siteURL
+}
+
+// LocalAttachmentLinks maps links to a ticket's attachments in a description pulled from
+// Jira back to the local files they were uploaded from
+func LocalAttachmentLinks(description string, attachments []types.Attachment) string {
+	if len(attachments) == 0 {
+		return description
+	}
+
+	return convert.RewriteLinks(description, func(target string, image bool) string {
+		for _, attachment := range attachments {
+			name := path.Base(attachment.Path)
+			if target == name || target == "^"+name || strings.HasSuffix(target, "/secure/attachment/"+attachment.ID+"/"+name) {
+				return attachment.Path
+			}
+		}
+		return ""
+	})
+}
+
+// findAttachment returns the attachment uploaded for a link target, if any
+func findAttachment(attachments []types.Attachment, target string) *types.Attachment {
+	for i, attachment := range attachments {
+		if attachment.Path == target {
+			return &attachments[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// Authentication modes for jira.auth
+const (
+	AuthBasic  = "basic"  // Username and API token (Jira Cloud)
+	AuthBearer = "bearer" // Personal access token (Jira Server / Data Center)
+	AuthOAuth  = "oauth"  // OAuth 2.0 (3LO) access token read from a token file (Jira Cloud)
+)
+
+// atlassianAPIURL is the gateway OAuth 2.0 apps use to reach a Jira Cloud site
+const atlassianAPIURL = "https://api.atlassian.com/ex/jira/"
+
+// atlassianTokenURL is where OAuth 2.0 access tokens are refreshed
+const atlassianTokenURL = "https://auth.atlassian.com/oauth/token"
+
+// AuthMode returns the configured authentication mode, defaulting to basic auth
+func AuthMode(config *types.Config) string {
+	switch mode := strings.ToLower(config.Jira.Auth); mode {
+	case "", AuthBasic:
+		return AuthBasic
+	case "pat":
+		return AuthBearer
+	default:
+		return mode
+	}
+}
+
+// CheckAuthConfig returns what is missing from the config for the configured
+// authentication mode, or nil when it is complete
+func CheckAuthConfig(config *types.Config) []string {
+	var missing []string
+	switch AuthMode(config) {
+	case AuthBasic:
+		if config.Jira.URL == "" {
+			missing = append(missing, "URL not set")
+		}
+		if config.Jira.Username == "" {
+			missing = append(missing, "Username not set")
+		}
+		if config.Jira.Token == "" {
+			missing = append(missing, "Token not set (set JAI_JIRA_TOKEN environment variable)")
+		}
+	case AuthBearer:
+		if config.Jira.URL == "" {
+			missing = append(missing, "URL not set")
+		}
+		if config.Jira.Token == "" {
+			missing = append(missing, "Personal access token not set (set JAI_JIRA_TOKEN environment variable)")
+		}
+	case AuthOAuth:
+		if config.Jira.OAuth.TokenFile == "" {
+			missing = append(missing, "OAuth token file not set (jira.oauth.token_file)")
+		}
+	default:
+		missing = append(missing, fmt.Sprintf("Unknown auth mode %q (use basic, bearer or oauth)", config.Jira.Auth))
+	}
+	return missing
+}
+
+// newHTTPClient builds the authenticated HTTP client and base URL for the configured auth mode
+func newHTTPClient(config *types.Config) (*http.Client, string, error) {
+	retry := newRetryTransport(time.Duration(config.Jira.Timeout)*time.Second, config.Jira.MaxRetries)
+
+	switch AuthMode(config) {
+	case AuthBasic:
+		tp := jira.BasicAuthTransport{
+			Username:  config.Jira.Username,
+			Password:  config.Jira.Token,
+			Transport: retry,
+		}
+		return tp.Client(), config.Jira.URL, nil
+
+	case AuthBearer:
+		tp := jira.BearerAuthTransport{
+			Token:     config.Jira.Token,
+			Transport: retry,
+		}
+		return tp.Client(), config.Jira.URL, nil
+
+	case AuthOAuth:
+		tp := &oauthTransport{
+			transport:    retry,
+			tokenFile:    config.Jira.OAuth.TokenFile,
+			clientID:     config.Jira.OAuth.ClientID,
+			clientSecret: config.Jira.OAuth.ClientSecret,
+		}
+		token, err := tp.load()
+		if err != nil {
+			return nil, "", err
+		}
+
+		// OAuth apps go through the Atlassian API gateway rather than the site URL
+		cloudID := config.Jira.OAuth.CloudID
+		if cloudID == "" {
+			cloudID = token.CloudID
+		}
+		if cloudID == "" {
+			return nil, "", fmt.Errorf("OAuth cloud ID not set (jira.oauth.cloud_id or cloud_id in the token file)")
+		}
+		return &http.Client{Transport: tp}, atlassianAPIURL + cloudID, nil
+
+	default:
+		return nil, "", fmt.Errorf("unknown Jira auth mode %q (use basic, bearer or oauth)", config.Jira.Auth)
+	}
+}
+
+// apiVersionFor returns the REST API version to write descriptions and comments with.
+// Jira Server and Data Center only speak v2.
+func apiVersionFor(config *types.Config) string {
+	version := strings.TrimPrefix(config.Jira.APIVersion, "v")
+	if AuthMode(config) == AuthBearer && version == "3" {
+		log.Printf("Warning: Jira Server / Data Center has no REST API v3, using v2")
+		return "2"
+	}
+	if version == "" {
+		return "2"
+	}
+	return version
+}
+
+// oauthToken is the token file written by the OAuth 2.0 (3LO) authorization flow
+type oauthToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+	CloudID      string    `json:"cloud_id,omitempty"`
+}
+
+// expired reports whether the token is expired or about to expire
+func (t *oauthToken) expired() bool {
+	return !t.ExpiresAt.IsZero() && time.Now().Add(time.Minute).After(t.ExpiresAt)
+}
+
+// oauthTransport adds the OAuth access token to each request, refreshing it
+// through Atlassian when it has expired and client credentials are configured
+type oauthTransport struct {
+	transport    http.RoundTripper
+	tokenFile    string
+	clientID     string
+	clientSecret string
+
+	mu    sync.Mutex
+	token *oauthToken
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *oauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.accessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// Requests must not be modified by a RoundTripper
+	req2 := req.Clone(req.Context())
+	req2.Header.Set("Authorization", "Bearer "+token)
+	return t.transport.RoundTrip(req2)
+}
+
+// accessToken returns a valid access token, refreshing it first if needed
+func (t *oauthTransport) accessToken() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == nil {
+		if _, err := t.loadLocked(); err != nil {
+			return "", err
+		}
+	}
+	if t.token.expired() {
+		if err := t.refreshLocked(); err != nil {
+			return "", err
+		}
+	}
+	return t.token.AccessToken, nil
+}
+
+// load reads the token file
+func (t *oauthTransport) load() (*oauthToken, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.loadLocked()
+}
+
+func (t *oauthTransport) loadLocked() (*oauthToken, error) {
+	data, err := os.ReadFile(t.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OAuth token file: %w", err)
+	}
+
+	var token oauthToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse OAuth token file: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("OAuth token file %s has no access_token", t.tokenFile)
+	}
+
+	t.token = &token
+	return &token, nil
+}
+
+// refreshLocked exchanges the refresh token for a new access token and saves it
+func (t *oauthTransport) refreshLocked() error {
+	if t.token.RefreshToken == "" || t.clientID == "" || t.clientSecret == "" {
+		return fmt.Errorf("OAuth access token expired at %s; refresh it or configure jira.oauth.client_id and JAI_JIRA_OAUTH_SECRET",
+			t.token.ExpiresAt.Format(time.RFC3339))
+	}
+
+	body, _ := json.Marshal(map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     t.clientID,
+		"client_secret": t.clientSecret,
+		"refresh_token": t.token.RefreshToken,
+	})
+	resp, err := http.Post(atlassianTokenURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to refresh OAuth token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to refresh OAuth token: %s", resp.Status)
+	}
+
+	var refreshed struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&refreshed); err != nil {
+		return fmt.Errorf("failed to parse OAuth token response: %w", err)
+	}
+
+	t.token.AccessToken = refreshed.AccessToken
+	if refreshed.RefreshToken != "" {
+		// Atlassian rotates refresh tokens
+		t.token.RefreshToken = refreshed.RefreshToken
+	}
+	t.token.ExpiresAt = time.Now().Add(time.Duration(refreshed.ExpiresIn) * time.Second)
+
+	data, err := json.MarshalIndent(t.token, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal OAuth token: %w", err)
+	}
+	if err := os.WriteFile(t.tokenFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write OAuth token file: %w", err)
+	}
+	return nil
+}
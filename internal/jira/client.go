@@ -2,29 +2,77 @@ package jira
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/andygrunwald/go-jira"
+	"github.com/lunchboxsushi/jai/internal/convert"
+	"github.com/lunchboxsushi/jai/internal/tracker"
 	"github.com/lunchboxsushi/jai/internal/types"
 )
 
+// DefaultPageSize is the number of issues requested per search page when none is configured
+const DefaultPageSize = 100
+
+// DefaultSearchLimit is the most issues SearchTickets returns; use SearchAll for more
+const DefaultSearchLimit = 100
+
+// searchKeyChunk is how many keys SearchByKeys puts in a single key in (...) query
+const searchKeyChunk = 100
+
+// quotedKeyRe finds the issue keys quoted in Jira's search errors, e.g.
+// "An issue with key 'OBS-12' does not exist for field 'key'."
+var quotedKeyRe = regexp.MustCompile(`'([A-Za-z][A-Za-z0-9_]*-\d+)'`)
+
+// SearchOptions controls how search results are paged
+type SearchOptions struct {
+	PageSize int // Issues requested per page
+	Limit    int // Maximum number of issues to return, 0 for no limit
+}
+
+var _ tracker.Backend = (*Client)(nil)
+
 // Client handles Jira API interactions
 type Client struct {
 	client *jira.Client
 	config *types.Config
+
+	// Epic Link field lookup, resolved at most once per client
+	epicLinkResolved bool
+	epicLinkField    string
+	epicLinkErr      error
+
+	// Field schemas for custom field conversion, resolved at most once per client
+	schemasResolved bool
+	schemas         map[string]jira.FieldSchema
+	schemasErr      error
+
+	// Sprint field lookup, resolved at most once per client
+	sprintResolved bool
+	sprintField    string
+
+	// Project style and issue type names, resolved at most once per client
+	project    *projectInfo
+	projectErr error
+
+	// Site URL for links people open in a browser, resolved at most once per client
+	siteResolved bool
+	siteURL      string
 }
 
 // NewClient creates a new Jira client
 func NewClient(config *types.Config) (*Client, error) {
-	tp := jira.BasicAuthTransport{
-		Username: config.Jira.Username,
-		Password: config.Jira.Token,
+	httpClient, baseURL, err := newHTTPClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Jira client: %w", err)
 	}
+	config.Jira.APIVersion = apiVersionFor(config)
 
-	client, err := jira.NewClient(tp.Client(), config.Jira.URL)
+	client, err := jira.NewClient(httpClient, baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Jira client: %w", err)
 	}
@@ -44,28 +92,16 @@ func (c *Client) CreateTicket(ticket *types.Ticket) (*types.Ticket, error) {
 			Project: jira.Project{
 				Key: c.config.Jira.Project,
 			},
-			Summary:     ticket.Title,
-			Description: ticket.Description,
+			Summary: ticket.Title,
 			Type: jira.IssueType{
 				Name: c.getIssueTypeName(ticket.Type),
 			},
 		},
 	}
 
-	// Set epic link if this is a task or subtask
+	// Link tasks to their epic
 	if ticket.Type == types.TicketTypeTask && ticket.EpicKey != "" {
-		// Get the epic link custom field ID
-		epicLinkField, err := c.GetEpicLinkField()
-		if err != nil {
-			log.Printf("Warning: Failed to get epic link field: %v", err)
-		} else {
-			// Set the epic link using custom fields
-			if issue.Fields.Unknowns == nil {
-				issue.Fields.Unknowns = make(map[string]interface{})
-			}
-			issue.Fields.Unknowns[epicLinkField] = ticket.EpicKey
-			log.Printf("Setting epic link: %s = %s", epicLinkField, ticket.EpicKey)
-		}
+		c.setEpic(issue, ticket.EpicKey)
 	}
 
 	// Set parent for subtasks
@@ -75,37 +111,33 @@ func (c *Client) CreateTicket(ticket *types.Ticket) (*types.Ticket, error) {
 		}
 	}
 
-	// Set priority
-	if ticket.Priority != "" {
-		// Jira expects priority as a string, not an object
-		// Try setting it as a string directly
-		// For now, let's skip priority setting to avoid the 400 error
-		log.Printf("Note: Skipping priority setting to avoid Jira API error. Priority would be: %s", ticket.Priority)
-	}
+	// Set priority, labels, components, assignee, due date and custom fields
+	c.setOptionalFields(issue.Fields, ticket)
+	c.setCustomFields(issue.Fields, ticket)
 
-	// Log the issue fields being sent
-	issueJson, _ := json.MarshalIndent(issue, "", "  ")
-	log.Printf("Jira Issue Request Body:\n%s\n", string(issueJson))
+	var newIssue *jira.Issue
+	for retried := false; ; retried = true {
+		// Log the issue fields being sent
+		issueJson, _ := json.MarshalIndent(issue, "", "  ")
+		log.Printf("Jira Issue Request Body:\n%s\n", string(issueJson))
 
-	// Create the issue
-	newIssue, resp, err := c.client.Issue.Create(issue)
-	if err != nil {
-		log.Printf("Jira API call failed with error: %v", err)
-
-		// Try to read the response body for more details
-		if resp != nil && resp.Body != nil {
-			body, readErr := ioutil.ReadAll(resp.Body)
-			if readErr == nil {
-				log.Printf("Jira API Error Response Body:\n%s\n", string(body))
-			} else {
-				log.Printf("Failed to read response body: %v", readErr)
-			}
+		// Create the issue
+		created, resp, err := c.createIssue(issue, c.jiraDescription(ticket))
+		if err == nil {
 			resp.Body.Close()
+			newIssue = created
+			break
+		}
+		err = newAPIError(resp, err)
+
+		// Retry once without any optional fields Jira refused
+		var validation *ValidationError
+		if !retried && errors.As(err, &validation) && dropRejectedFields(issue.Fields, validation.Fields) {
+			continue
 		}
 
 		return nil, fmt.Errorf("failed to create Jira issue: %w", err)
 	}
-	defer resp.Body.Close()
 
 	log.Printf("Jira ticket created successfully - Key: %s, ID: %s", newIssue.Key, newIssue.ID)
 
@@ -122,83 +154,333 @@ func (c *Client) CreateTicket(ticket *types.Ticket) (*types.Ticket, error) {
 func (c *Client) GetTicket(key string) (*types.Ticket, error) {
 	issue, resp, err := c.client.Issue.Get(key, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get Jira issue: %w", err)
+		return nil, fmt.Errorf("failed to get Jira issue %s: %w", key, newAPIError(resp, err))
 	}
 	defer resp.Body.Close()
 
 	return c.convertJiraIssue(issue), nil
 }
 
-// UpdateTicket updates an existing ticket
+// UpdateTicket updates an existing ticket. An empty description leaves the one in Jira as it is.
 func (c *Client) UpdateTicket(ticket *types.Ticket) error {
 	issue := &jira.Issue{
 		Key: ticket.Key,
 		Fields: &jira.IssueFields{
-			Summary:     ticket.Title,
-			Description: ticket.Description,
+			Summary: ticket.Title,
 		},
 	}
 
 	if ticket.Priority != "" {
-		issue.Fields.Priority = &jira.Priority{
-			Name: ticket.Priority,
-		}
+		issue.Fields.Priority = priorityFor(ticket.Priority)
 	}
+	c.setCustomFields(issue.Fields, ticket)
 
-	_, resp, err := c.client.Issue.Update(issue)
+	resp, err := c.updateIssue(issue, c.jiraDescription(ticket))
 	if err != nil {
-		return fmt.Errorf("failed to update Jira issue: %w", err)
+		return fmt.Errorf("failed to update Jira issue %s: %w", ticket.Key, newAPIError(resp, err))
 	}
 	defer resp.Body.Close()
 
 	return nil
 }
 
-// SearchTickets searches for tickets using JQL
-func (c *Client) SearchTickets(jql string) ([]*types.Ticket, error) {
-	opts := &jira.SearchOptions{
-		MaxResults: 100,
-		StartAt:    0,
+// Transition is a workflow transition that can be performed on a ticket
+type Transition struct {
+	ID       string
+	Name     string
+	ToStatus string
+}
+
+// GetTransitions returns the workflow transitions currently available for a ticket
+func (c *Client) GetTransitions(key string) ([]Transition, error) {
+	jiraTransitions, resp, err := c.client.Issue.GetTransitions(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transitions for %s: %w", key, newAPIError(resp, err))
+	}
+	defer resp.Body.Close()
+
+	var transitions []Transition
+	for _, t := range jiraTransitions {
+		transitions = append(transitions, Transition{
+			ID:       t.ID,
+			Name:     t.Name,
+			ToStatus: t.To.Name,
+		})
+	}
+
+	return transitions, nil
+}
+
+// TransitionTicket performs a workflow transition on a ticket
+func (c *Client) TransitionTicket(key, transitionID string) error {
+	resp, err := c.client.Issue.DoTransition(key, transitionID)
+	if err != nil {
+		return fmt.Errorf("failed to transition %s: %w", key, newAPIError(resp, err))
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// Transition performs the transition whose target status or name best matches status and
+// returns the status the ticket moved to
+func (c *Client) Transition(key, status string) (string, error) {
+	transitions, err := c.GetTransitions(key)
+	if err != nil {
+		return "", err
+	}
+	transition, err := MatchTransition(transitions, status)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", key, err)
+	}
+	if err := c.TransitionTicket(key, transition.ID); err != nil {
+		return "", err
+	}
+	return transition.ToStatus, nil
+}
+
+// MatchTransition picks the transition whose name or target status best matches the query
+func MatchTransition(transitions []Transition, query string) (*Transition, error) {
+	if len(transitions) == 0 {
+		return nil, fmt.Errorf("no transitions available")
+	}
+
+	normalized := tracker.NormalizeStatus(query)
+
+	// Exact matches on the target status win over transition names
+	for i, t := range transitions {
+		if tracker.NormalizeStatus(t.ToStatus) == normalized {
+			return &transitions[i], nil
+		}
+	}
+	for i, t := range transitions {
+		if tracker.NormalizeStatus(t.Name) == normalized {
+			return &transitions[i], nil
+		}
+	}
+
+	// Fall back to substring matching, but only accept an unambiguous result
+	var matches []*Transition
+	for i, t := range transitions {
+		if strings.Contains(tracker.NormalizeStatus(t.ToStatus), normalized) || strings.Contains(tracker.NormalizeStatus(t.Name), normalized) {
+			matches = append(matches, &transitions[i])
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+
+	var available []string
+	for _, t := range transitions {
+		available = append(available, fmt.Sprintf("%q → %s", t.Name, t.ToStatus))
 	}
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("%q matches more than one transition, available: %s", query, strings.Join(available, ", "))
+	}
+	return nil, fmt.Errorf("no transition matches %q, available: %s", query, strings.Join(available, ", "))
+}
+
+// GetComments returns all comments on a ticket, oldest first
+func (c *Client) GetComments(key string) ([]types.Comment, error) {
+	issue, resp, err := c.client.Issue.Get(key, &jira.GetQueryOptions{Fields: "comment"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments for %s: %w", key, newAPIError(resp, err))
+	}
+	defer resp.Body.Close()
+
+	if issue.Fields == nil || issue.Fields.Comments == nil {
+		return nil, nil
+	}
+	return convertJiraComments(issue.Fields.Comments.Comments), nil
+}
 
-	issues, resp, err := c.client.Issue.Search(jql, opts)
+// AddComment posts a comment on a ticket
+func (c *Client) AddComment(key, body string) (*types.Comment, error) {
+	comment, resp, err := c.addComment(key, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search Jira issues: %w", err)
+		return nil, fmt.Errorf("failed to add comment to %s: %w", key, newAPIError(resp, err))
 	}
 	defer resp.Body.Close()
 
+	return comment, nil
+}
+
+// AddWorklog logs time spent on a ticket and returns the Jira worklog ID
+func (c *Client) AddWorklog(key string, started time.Time, timeSpent time.Duration, comment string) (string, error) {
+	startedAt := jira.Time(started)
+	record := &jira.WorklogRecord{
+		Comment:          comment,
+		Started:          &startedAt,
+		TimeSpentSeconds: int(timeSpent.Seconds()),
+	}
+
+	worklog, resp, err := c.client.Issue.AddWorklogRecord(key, record)
+	if err != nil {
+		return "", fmt.Errorf("failed to add worklog to %s: %w", key, newAPIError(resp, err))
+	}
+	defer resp.Body.Close()
+
+	return worklog.ID, nil
+}
+
+// convertJiraComments converts Jira comments to our comment type
+func convertJiraComments(jiraComments []*jira.Comment) []types.Comment {
+	var comments []types.Comment
+	for _, jc := range jiraComments {
+		if jc == nil {
+			continue
+		}
+		created, _ := time.Parse("2006-01-02T15:04:05.999-0700", jc.Created)
+		author := jc.Author.DisplayName
+		if author == "" {
+			author = jc.Author.Name
+		}
+		comments = append(comments, types.Comment{
+			ID:      jc.ID,
+			Author:  author,
+			Created: created,
+			Body:    strings.TrimSpace(convert.WikiToMarkdown(jc.Body)),
+		})
+	}
+	return comments
+}
+
+// SearchTickets searches for tickets using JQL, returning at most DefaultSearchLimit of them
+func (c *Client) SearchTickets(jql string) ([]*types.Ticket, error) {
+	return c.SearchAll(jql, SearchOptions{Limit: DefaultSearchLimit})
+}
+
+// SearchAll pages through every issue matching a JQL query, up to opts.Limit. Without a
+// page size, jira.page_size or DefaultPageSize is used.
+func (c *Client) SearchAll(jql string, opts SearchOptions) ([]*types.Ticket, error) {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = c.config.Jira.PageSize
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
 	var tickets []*types.Ticket
-	for _, issue := range issues {
-		tickets = append(tickets, c.convertJiraIssue(&issue))
+	startAt := 0
+	for {
+		maxResults := pageSize
+		if opts.Limit > 0 && opts.Limit-len(tickets) < maxResults {
+			maxResults = opts.Limit - len(tickets)
+		}
+
+		issues, resp, err := c.client.Issue.Search(jql, &jira.SearchOptions{
+			MaxResults: maxResults,
+			StartAt:    startAt,
+			Fields:     []string{"*navigable", "comment"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search Jira issues: %w", newAPIError(resp, err))
+		}
+		resp.Body.Close()
+
+		for _, issue := range issues {
+			tickets = append(tickets, c.convertJiraIssue(&issue))
+		}
+
+		// Jira may cap the page size below what we asked for, so advance by what came back
+		startAt += len(issues)
+		if len(issues) == 0 || startAt >= resp.Total {
+			break
+		}
+		if opts.Limit > 0 && len(tickets) >= opts.Limit {
+			break
+		}
 	}
 
 	return tickets, nil
 }
 
+// SearchByKeys returns the issues with the given keys that also match a JQL condition, if
+// one is given, querying a chunk of keys at a time. A key that doesn't exist or can't be
+// seen fails the whole query, so keys Jira rejects are dropped from the query and returned.
+func (c *Client) SearchByKeys(keys []string, condition string) ([]*types.Ticket, []string, error) {
+	var tickets []*types.Ticket
+	var rejected []string
+	for start := 0; start < len(keys); start += searchKeyChunk {
+		chunk := keys[start:min(start+searchKeyChunk, len(keys))]
+		for len(chunk) > 0 {
+			jql := fmt.Sprintf("key in (%s)", strings.Join(chunk, ", "))
+			if condition != "" {
+				jql += " AND " + condition
+			}
+			found, err := c.SearchAll(jql+" ORDER BY updated ASC", SearchOptions{Limit: len(chunk)})
+			if err == nil {
+				tickets = append(tickets, found...)
+				break
+			}
+
+			bad := rejectedKeys(err, chunk)
+			if len(bad) == 0 {
+				return nil, nil, err
+			}
+			var kept []string
+			for _, key := range chunk {
+				if bad[key] {
+					rejected = append(rejected, key)
+				} else {
+					kept = append(kept, key)
+				}
+			}
+			chunk = kept
+		}
+	}
+	return tickets, rejected, nil
+}
+
+// rejectedKeys returns the keys of a query that a Jira validation error complains about
+func rejectedKeys(err error, keys []string) map[string]bool {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+	rejected := make(map[string]bool)
+	for _, message := range validationErr.Messages {
+		for _, m := range quotedKeyRe.FindAllStringSubmatch(message, -1) {
+			for _, key := range keys {
+				if strings.EqualFold(key, m[1]) {
+					rejected[key] = true
+				}
+			}
+		}
+	}
+	return rejected
+}
+
 // convertJiraIssue converts a Jira issue to our Ticket type
 func (c *Client) convertJiraIssue(issue *jira.Issue) *types.Ticket {
 	ticket := &types.Ticket{
 		Key:         issue.Key,
 		ID:          issue.ID,
 		Title:       issue.Fields.Summary,
-		Description: issue.Fields.Description,
+		Description: convert.WikiToMarkdown(issue.Fields.Description),
 		Status:      issue.Fields.Status.Name,
 		Labels:      issue.Fields.Labels,
 		Created:     time.Time(issue.Fields.Created),
 		Updated:     time.Time(issue.Fields.Updated),
 	}
 
-	// Determine ticket type
-	switch issue.Fields.Type.Name {
-	case "Epic":
-		ticket.Type = types.TicketTypeEpic
-	case "Sub-task":
+	// Determine ticket type. Subtask types are flagged by Jira, whatever they are called
+	// ("Sub-task" in company-managed projects, "Subtask" in team-managed ones).
+	switch {
+	case issue.Fields.Type.Subtask || issue.Fields.Type.Name == "Sub-task" || issue.Fields.Type.Name == "Subtask":
 		ticket.Type = types.TicketTypeSubtask
 		if issue.Fields.Parent != nil {
 			ticket.ParentKey = issue.Fields.Parent.Key
 		}
+	case c.isEpicType(issue.Fields.Type.Name):
+		ticket.Type = types.TicketTypeEpic
 	default:
 		ticket.Type = types.TicketTypeTask
+		// Team-managed projects (and newer company-managed ones) link epics through the parent
+		if issue.Fields.Parent != nil {
+			ticket.EpicKey = issue.Fields.Parent.Key
+		}
 	}
 
 	// Set priority
@@ -206,11 +488,40 @@ func (c *Client) convertJiraIssue(issue *jira.Issue) *types.Ticket {
 		ticket.Priority = issue.Fields.Priority.Name
 	}
 
-	// Note: Epic linking would require custom field handling
-	// For now, we'll skip this as it's complex to implement
+	// Set components, assignee and due date
+	for _, component := range issue.Fields.Components {
+		ticket.Components = append(ticket.Components, component.Name)
+	}
+	if issue.Fields.Assignee != nil {
+		ticket.Assignee = issue.Fields.Assignee.EmailAddress
+		if ticket.Assignee == "" {
+			ticket.Assignee = issue.Fields.Assignee.DisplayName
+		}
+	}
+	if due := time.Time(issue.Fields.Duedate); !due.IsZero() {
+		ticket.DueDate = &due
+	}
 
-	// Extract epic link if present
+	// Set comments
+	if issue.Fields.Comments != nil {
+		ticket.Comments = convertJiraComments(issue.Fields.Comments.Comments)
+	}
+
+	// Set issue links
+	ticket.Links = convertJiraLinks(issue.Fields.IssueLinks)
+
+	// Set custom fields mapped in jira.custom_fields
+	ticket.CustomFields = c.customFieldsFromIssue(issue)
+
+	// Set the sprint the ticket is planned in
 	if issue.Fields.Unknowns != nil {
+		if sprintField := c.GetSprintField(); sprintField != "" {
+			ticket.Sprint = sprintName(issue.Fields.Unknowns[sprintField])
+		}
+	}
+
+	// Extract epic link if present
+	if ticket.Type == types.TicketTypeTask && ticket.EpicKey == "" && issue.Fields.Unknowns != nil {
 		if epicLinkField, err := c.GetEpicLinkField(); err == nil {
 			if epicKey, ok := issue.Fields.Unknowns[epicLinkField].(string); ok {
 				ticket.EpicKey = epicKey
@@ -223,32 +534,52 @@ func (c *Client) convertJiraIssue(issue *jira.Issue) *types.Ticket {
 
 // getIssueTypeName returns the Jira issue type name for our ticket type
 func (c *Client) getIssueTypeName(ticketType types.TicketType) string {
+	// Falls back to the company-managed names if the project can't be read
+	info, _ := c.getProjectInfo()
 	switch ticketType {
 	case types.TicketTypeEpic:
-		return "Epic"
+		return info.EpicType
 	case types.TicketTypeSubtask:
-		return "Sub-task"
+		return info.SubtaskType
 	default:
-		return "Task"
+		return info.TaskType
+	}
+}
+
+// isEpicType reports whether an issue type name is the epic type
+func (c *Client) isEpicType(name string) bool {
+	if name == "Epic" {
+		return true
 	}
+	if c.project != nil {
+		return name == c.project.EpicType
+	}
+	return false
 }
 
-// GetEpicLinkField returns the custom field ID for epic links
-func (c *Client) GetEpicLinkField() (string, error) {
-	// Check if configured in config first
-	if c.config.Jira.EpicLinkField != "" {
-		return c.config.Jira.EpicLinkField, nil
+// setEpic links an issue to its epic the way the project expects
+func (c *Client) setEpic(issue *jira.Issue, epicKey string) {
+	info, err := c.getProjectInfo()
+	if err != nil {
+		log.Printf("Warning: Failed to read project %s, assuming %s-managed: %v", c.config.Jira.Project, info.Style, err)
 	}
 
-	// Common epic link field IDs for different Jira setups
-	// These are the most common field IDs used for epic linking
-	commonEpicFields := []string{
-		"customfield_10014", // Most common
-		"customfield_10008", // Alternative
-		"customfield_10016", // Another common one
+	if info.Style == ProjectStyleCompany {
+		epicLinkField, err := c.GetEpicLinkField()
+		if err == nil {
+			// Set the epic link using custom fields
+			if issue.Fields.Unknowns == nil {
+				issue.Fields.Unknowns = make(map[string]interface{})
+			}
+			issue.Fields.Unknowns[epicLinkField] = epicKey
+			log.Printf("Setting epic link: %s = %s", epicLinkField, epicKey)
+			return
+		}
+		log.Printf("Warning: Failed to get epic link field, linking epic through parent: %v", err)
 	}
 
-	// For now, return the most common one
-	// In a full implementation, you could query the Jira API to discover the correct field
-	return commonEpicFields[0], nil
+	issue.Fields.Parent = &jira.Parent{
+		Key: epicKey,
+	}
+	log.Printf("Setting epic parent: %s", epicKey)
 }
@@ -0,0 +1,291 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// createMetaTTL is how long a project's create metadata is cached before it is fetched again
+const createMetaTTL = 24 * time.Hour
+
+// CreateField describes a field on a project's create screen
+type CreateField struct {
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	Required      bool             `json:"required"`
+	HasDefault    bool             `json:"has_default"`
+	Schema        jira.FieldSchema `json:"schema"`
+	AllowedValues []string         `json:"allowed_values,omitempty"`
+}
+
+// createMetaCache is the on-disk record of create metadata, per project and issue type
+type createMetaCache struct {
+	URL        string                     `json:"url"`
+	IssueTypes map[string]createMetaEntry `json:"issue_types"` // Keyed by "PROJECT/Issue Type"
+}
+
+// createMetaEntry is the cached create metadata for one issue type
+type createMetaEntry struct {
+	Fetched time.Time     `json:"fetched"`
+	Fields  []CreateField `json:"fields"`
+}
+
+// createMetaField is a field as returned by Jira's createmeta endpoints
+type createMetaField struct {
+	FieldID         string                   `json:"fieldId"`
+	Key             string                   `json:"key"`
+	Name            string                   `json:"name"`
+	Required        bool                     `json:"required"`
+	HasDefaultValue bool                     `json:"hasDefaultValue"`
+	Schema          jira.FieldSchema         `json:"schema"`
+	AllowedValues   []map[string]interface{} `json:"allowedValues"`
+}
+
+// alwaysSent are the fields every create request carries, or that Jira fills in itself
+var alwaysSent = map[string]bool{"project": true, "issuetype": true, "summary": true, "reporter": true}
+
+// MissingRequiredFields returns the fields Jira requires to create the ticket that jai
+// wouldn't send and that have no default value
+func (c *Client) MissingRequiredFields(ticket *types.Ticket) ([]CreateField, error) {
+	fields, err := c.GetCreateFields(ticket.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	sent := c.sentFields(ticket)
+	var missing []CreateField
+	for _, field := range fields {
+		if field.Required && !field.HasDefault && !sent[field.ID] {
+			missing = append(missing, field)
+		}
+	}
+	return missing, nil
+}
+
+// sentFields returns the IDs of the fields CreateTicket would set for a ticket
+func (c *Client) sentFields(ticket *types.Ticket) map[string]bool {
+	sent := make(map[string]bool)
+	for id := range alwaysSent {
+		sent[id] = true
+	}
+
+	set := func(id string, ok bool) {
+		if ok {
+			sent[id] = true
+		}
+	}
+	set("description", strings.TrimSpace(ticket.Description) != "")
+	set("priority", ticket.Priority != "")
+	set("labels", len(ticket.Labels) > 0)
+	set("components", len(ticket.Components) > 0)
+	set("assignee", ticket.Assignee != "")
+	set("duedate", ticket.DueDate != nil)
+	set("parent", ticket.ParentKey != "" || ticket.EpicKey != "")
+	if ticket.Type == types.TicketTypeTask && ticket.EpicKey != "" {
+		if epicLinkField, err := c.GetEpicLinkField(); err == nil {
+			sent[epicLinkField] = true
+		}
+	}
+
+	for name, value := range ticket.CustomFields {
+		if strings.TrimSpace(fmt.Sprint(value)) == "" {
+			continue
+		}
+		if id := CustomFieldID(c.config, name); id != "" {
+			sent[id] = true
+		}
+	}
+	return sent
+}
+
+// GetCreateFields returns the fields on the create screen for a ticket type in the
+// configured project, cached in the data directory for a day
+func (c *Client) GetCreateFields(ticketType types.TicketType) ([]CreateField, error) {
+	issueType := c.getIssueTypeName(ticketType)
+	cacheKey := c.config.Jira.Project + "/" + issueType
+
+	cache := c.loadCreateMetaCache()
+	if entry, ok := cache.IssueTypes[cacheKey]; ok && time.Since(entry.Fetched) < createMetaTTL {
+		return entry.Fields, nil
+	}
+
+	fields, err := c.fetchCreateFields(issueType)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.IssueTypes[cacheKey] = createMetaEntry{Fetched: time.Now(), Fields: fields}
+	c.saveCreateMetaCache(cache)
+	return fields, nil
+}
+
+// fetchCreateFields reads the create metadata for an issue type from Jira. Newer instances
+// serve it per issue type; older Jira Server versions only have the combined endpoint.
+func (c *Client) fetchCreateFields(issueType string) ([]CreateField, error) {
+	project := url.PathEscape(c.config.Jira.Project)
+
+	var issueTypes struct {
+		Values     []struct{ ID, Name string } `json:"values"`
+		IssueTypes []struct{ ID, Name string } `json:"issueTypes"`
+	}
+	resp, err := c.getJSON(fmt.Sprintf("rest/api/2/issue/createmeta/%s/issuetypes", project), &issueTypes)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return c.fetchLegacyCreateFields(issueType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get create metadata for %s: %w", c.config.Jira.Project, newAPIError(resp, err))
+	}
+
+	typeID := ""
+	for _, t := range append(issueTypes.Values, issueTypes.IssueTypes...) {
+		if strings.EqualFold(t.Name, issueType) {
+			typeID = t.ID
+			break
+		}
+	}
+	if typeID == "" {
+		return nil, fmt.Errorf("project %s has no issue type %q", c.config.Jira.Project, issueType)
+	}
+
+	var page struct {
+		Values []createMetaField `json:"values"`
+		Fields []createMetaField `json:"fields"`
+	}
+	resp, err = c.getJSON(fmt.Sprintf("rest/api/2/issue/createmeta/%s/issuetypes/%s?maxResults=1000", project, typeID), &page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get create metadata for %s: %w", issueType, newAPIError(resp, err))
+	}
+
+	var fields []CreateField
+	for _, f := range append(page.Values, page.Fields...) {
+		fields = append(fields, f.toCreateField(f.FieldID))
+	}
+	return fields, nil
+}
+
+// fetchLegacyCreateFields reads create metadata from the combined createmeta endpoint
+func (c *Client) fetchLegacyCreateFields(issueType string) ([]CreateField, error) {
+	var meta struct {
+		Projects []struct {
+			IssueTypes []struct {
+				Name   string                     `json:"name"`
+				Fields map[string]createMetaField `json:"fields"`
+			} `json:"issuetypes"`
+		} `json:"projects"`
+	}
+	query := url.Values{
+		"projectKeys":    {c.config.Jira.Project},
+		"issuetypeNames": {issueType},
+		"expand":         {"projects.issuetypes.fields"},
+	}
+	resp, err := c.getJSON("rest/api/2/issue/createmeta?"+query.Encode(), &meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get create metadata for %s: %w", c.config.Jira.Project, newAPIError(resp, err))
+	}
+
+	for _, project := range meta.Projects {
+		for _, t := range project.IssueTypes {
+			if !strings.EqualFold(t.Name, issueType) {
+				continue
+			}
+			var fields []CreateField
+			for id, f := range t.Fields {
+				fields = append(fields, f.toCreateField(id))
+			}
+			return fields, nil
+		}
+	}
+	return nil, fmt.Errorf("project %s has no issue type %q", c.config.Jira.Project, issueType)
+}
+
+// toCreateField converts a createmeta field, keeping the names of its allowed values
+func (f createMetaField) toCreateField(id string) CreateField {
+	if id == "" {
+		id = f.Key
+	}
+	field := CreateField{
+		ID:         id,
+		Name:       f.Name,
+		Required:   f.Required,
+		HasDefault: f.HasDefaultValue,
+		Schema:     f.Schema,
+	}
+	for _, allowed := range f.AllowedValues {
+		for _, key := range []string{"value", "name", "key"} {
+			if name, ok := allowed[key].(string); ok && name != "" {
+				field.AllowedValues = append(field.AllowedValues, name)
+				break
+			}
+		}
+	}
+	return field
+}
+
+// getJSON performs a GET request against the Jira API and decodes the response into v
+func (c *Client) getJSON(endpoint string, v interface{}) (*jira.Response, error) {
+	req, err := c.client.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req, v)
+	if err != nil {
+		return resp, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// createMetaCachePath returns where create metadata is cached, or "" if there is no data directory
+func (c *Client) createMetaCachePath() string {
+	if c.config.General.DataDir == "" {
+		return ""
+	}
+	return filepath.Join(c.config.General.DataDir, "jira_createmeta.json")
+}
+
+// loadCreateMetaCache returns the cached create metadata for the configured instance
+func (c *Client) loadCreateMetaCache() createMetaCache {
+	cache := createMetaCache{URL: c.config.Jira.URL, IssueTypes: make(map[string]createMetaEntry)}
+	path := c.createMetaCachePath()
+	if path == "" {
+		return cache
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	var stored createMetaCache
+	if err := json.Unmarshal(data, &stored); err != nil || stored.URL != c.config.Jira.URL || stored.IssueTypes == nil {
+		return cache
+	}
+	return stored
+}
+
+// saveCreateMetaCache writes create metadata to the data directory. Failures only cost
+// another lookup next time, so they are not reported.
+func (c *Client) saveCreateMetaCache(cache createMetaCache) {
+	path := c.createMetaCachePath()
+	if path == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0644)
+}
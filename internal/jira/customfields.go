@@ -0,0 +1,201 @@
+package jira
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/lunchboxsushi/jai/internal/convert"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// textareaSchemaType is the custom field type of multi-line text fields, which take the
+// same markup as the description
+const textareaSchemaType = "com.atlassian.jira.plugin.system.customfieldtypes:textarea"
+
+// CustomFieldID returns the Jira field ID a custom metadata field maps to through
+// jira.custom_fields, or "" if it isn't mapped. Names match regardless of case and
+// separators, so "StoryPoints" matches story_points; raw IDs like customfield_10016 are
+// used as they are.
+func CustomFieldID(config *types.Config, name string) string {
+	if strings.HasPrefix(strings.ToLower(name), "customfield_") {
+		return strings.ToLower(name)
+	}
+	normalized := normalizeFieldName(name)
+	for friendly, id := range config.Jira.CustomFields {
+		if normalizeFieldName(friendly) == normalized {
+			return id
+		}
+	}
+	return ""
+}
+
+// CustomFieldLabel returns the metadata label for a configured custom field name,
+// e.g. "StoryPoints" for story_points
+func CustomFieldLabel(name string) string {
+	var label strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == ' ' }) {
+		label.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return label.String()
+}
+
+// normalizeFieldName lowercases a field name and strips separators
+func normalizeFieldName(name string) string {
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// fieldSchemas returns the schema of every field on the Jira instance, keyed by field ID
+func (c *Client) fieldSchemas() (map[string]jira.FieldSchema, error) {
+	if c.schemasResolved {
+		return c.schemas, c.schemasErr
+	}
+	c.schemasResolved = true
+
+	fields, resp, err := c.client.Field.GetList()
+	if err != nil {
+		c.schemasErr = fmt.Errorf("failed to list Jira fields: %w", newAPIError(resp, err))
+		return nil, c.schemasErr
+	}
+	defer resp.Body.Close()
+
+	c.schemas = make(map[string]jira.FieldSchema, len(fields))
+	for _, field := range fields {
+		c.schemas[field.ID] = field.Schema
+	}
+	return c.schemas, nil
+}
+
+// setCustomFields maps a ticket's custom metadata fields onto an issue, converting each
+// value to the shape its field type expects. Fields that aren't mapped in jira.custom_fields,
+// or whose values can't be converted, are reported and left off.
+func (c *Client) setCustomFields(fields *jira.IssueFields, ticket *types.Ticket) {
+	if len(ticket.CustomFields) == 0 {
+		return
+	}
+
+	schemas, err := c.fieldSchemas()
+	if err != nil {
+		log.Printf("Warning: Not setting custom fields: %v", err)
+		return
+	}
+
+	names := make([]string, 0, len(ticket.CustomFields))
+	for name := range ticket.CustomFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		text := strings.TrimSpace(fmt.Sprint(ticket.CustomFields[name]))
+		id := CustomFieldID(c.config, name)
+		if id == "" || text == "" {
+			continue
+		}
+		schema, ok := schemas[id]
+		if !ok {
+			log.Printf("Warning: Not setting %s: Jira has no field %s", name, id)
+			continue
+		}
+
+		value, err := c.customFieldValue(schema, text)
+		if err != nil {
+			log.Printf("Warning: Not setting %s: %v", name, err)
+			continue
+		}
+		if fields.Unknowns == nil {
+			fields.Unknowns = map[string]interface{}{}
+		}
+		fields.Unknowns[id] = value
+	}
+}
+
+// customFieldValue converts a metadata value to what Jira expects for a field's type
+func (c *Client) customFieldValue(schema jira.FieldSchema, text string) (interface{}, error) {
+	switch schema.Type {
+	case "number":
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		return number, nil
+	case "option":
+		return map[string]string{"value": text}, nil
+	case "user":
+		return c.resolveUser(text)
+	case "array":
+		var values []interface{}
+		for _, item := range convert.SplitList(text) {
+			value, err := c.customFieldValue(jira.FieldSchema{Type: schema.Items}, item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case "version", "component":
+		return map[string]string{"name": text}, nil
+	case "string":
+		if schema.Custom == textareaSchemaType {
+			if c.useADF() {
+				return convert.MarkdownToADF(text), nil
+			}
+			return convert.MarkdownToWiki(text), nil
+		}
+	}
+	return text, nil
+}
+
+// customFieldsFromIssue reads the configured custom fields of an issue into metadata values.
+// Multi-line values don't fit on a metadata line, so they are left out rather than flattened.
+func (c *Client) customFieldsFromIssue(issue *jira.Issue) map[string]interface{} {
+	if len(c.config.Jira.CustomFields) == 0 || issue.Fields.Unknowns == nil {
+		return nil
+	}
+
+	var custom map[string]interface{}
+	for name, id := range c.config.Jira.CustomFields {
+		text := customFieldText(issue.Fields.Unknowns[id])
+		if text == "" || strings.Contains(text, "\n") {
+			continue
+		}
+		if custom == nil {
+			custom = make(map[string]interface{})
+		}
+		custom[CustomFieldLabel(name)] = text
+	}
+	return custom
+}
+
+// customFieldText renders a custom field value from Jira as metadata text
+func customFieldText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(convert.WikiToMarkdown(v))
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			if text := customFieldText(item); text != "" {
+				items = append(items, text)
+			}
+		}
+		return strings.Join(items, ", ")
+	case map[string]interface{}:
+		// Options have a value, users an email or display name, versions and components a name
+		for _, key := range []string{"value", "emailAddress", "displayName", "name", "key"} {
+			if text, ok := v[key].(string); ok && text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/lunchboxsushi/jai/internal/convert"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// useADF reports whether descriptions and comments are written through REST API v3,
+// which takes Atlassian Document Format instead of wiki markup
+func (c *Client) useADF() bool {
+	return c.config.Jira.APIVersion == "3"
+}
+
+// createIssue creates an issue with the given markdown description
+func (c *Client) createIssue(issue *jira.Issue, description string) (*jira.Issue, *jira.Response, error) {
+	if !c.useADF() {
+		issue.Fields.Description = convert.MarkdownToWiki(description)
+		return c.client.Issue.Create(issue)
+	}
+
+	fields, err := fieldsWithADF(issue.Fields, description)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := c.client.NewRequest("POST", "rest/api/3/issue", map[string]interface{}{"fields": fields})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	created := new(jira.Issue)
+	resp, err := c.client.Do(req, created)
+	if err != nil {
+		return nil, resp, err
+	}
+	return created, resp, nil
+}
+
+// updateIssue updates an issue with the given markdown description. An empty description
+// leaves the one in Jira untouched.
+func (c *Client) updateIssue(issue *jira.Issue, description string) (*jira.Response, error) {
+	if !c.useADF() {
+		if strings.TrimSpace(description) != "" {
+			issue.Fields.Description = convert.MarkdownToWiki(description)
+		}
+		_, resp, err := c.client.Issue.Update(issue)
+		return resp, err
+	}
+
+	fields, err := fieldsWithADF(issue.Fields, description)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.client.NewRequest("PUT", fmt.Sprintf("rest/api/3/issue/%s", issue.Key), map[string]interface{}{"fields": fields})
+	if err != nil {
+		return nil, err
+	}
+	return c.client.Do(req, nil)
+}
+
+// addComment posts a markdown comment and returns it as Jira recorded it
+func (c *Client) addComment(key, body string) (*types.Comment, *jira.Response, error) {
+	if !c.useADF() {
+		comment, resp, err := c.client.Issue.AddComment(key, &jira.Comment{Body: convert.MarkdownToWiki(body)})
+		if err != nil {
+			return nil, resp, err
+		}
+		converted := convertJiraComments([]*jira.Comment{comment})
+		return &converted[0], resp, nil
+	}
+
+	req, err := c.client.NewRequest("POST", fmt.Sprintf("rest/api/3/issue/%s/comment", key), map[string]interface{}{"body": convert.MarkdownToADF(body)})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// v3 returns the body as ADF, so keep the markdown we sent
+	var created struct {
+		ID      string    `json:"id"`
+		Author  jira.User `json:"author"`
+		Created string    `json:"created"`
+	}
+	resp, err := c.client.Do(req, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	comment := convertJiraComments([]*jira.Comment{{
+		ID:      created.ID,
+		Author:  created.Author,
+		Created: created.Created,
+	}})[0]
+	comment.Body = body
+	if comment.Created.IsZero() {
+		comment.Created = time.Now()
+	}
+	return &comment, resp, nil
+}
+
+// fieldsWithADF encodes issue fields for API v3 with the description as an ADF document
+func fieldsWithADF(fields *jira.IssueFields, description string) (map[string]interface{}, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode issue fields: %w", err)
+	}
+
+	var encoded map[string]interface{}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("failed to encode issue fields: %w", err)
+	}
+	if strings.TrimSpace(description) != "" {
+		encoded["description"] = convert.MarkdownToADF(description)
+	}
+	return encoded, nil
+}
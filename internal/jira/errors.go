@@ -0,0 +1,104 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// APIError is an error response from the Jira REST API
+type APIError struct {
+	StatusCode int
+	Messages   []string          // errorMessages from the response
+	Fields     map[string]string // errors keyed by field ID
+	Err        error             // Underlying error from the HTTP client
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	details := append([]string(nil), e.Messages...)
+
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		details = append(details, fmt.Sprintf("%s: %s", field, e.Fields[field]))
+	}
+
+	if len(details) == 0 {
+		return fmt.Sprintf("Jira returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("Jira returned %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), strings.Join(details, "; "))
+}
+
+// Unwrap returns the underlying HTTP client error
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// AuthError means Jira did not accept the credentials (401)
+type AuthError struct{ *APIError }
+
+// PermissionError means the user may not perform the request (403)
+type PermissionError struct{ *APIError }
+
+// NotFoundError means the issue, project or resource does not exist or is not visible (404)
+type NotFoundError struct{ *APIError }
+
+// ValidationError means Jira rejected the request's fields (400); Fields holds a message per field
+type ValidationError struct{ *APIError }
+
+// RateLimitError means Jira kept rate limiting the request after every retry (429)
+type RateLimitError struct{ *APIError }
+
+// newAPIError converts a failed go-jira call into one of the typed errors above.
+// Errors that never got a response (network failures, timeouts) are returned unchanged.
+func newAPIError(resp *jira.Response, err error) error {
+	if err == nil || resp == nil || resp.Response == nil || resp.StatusCode < 400 {
+		return err
+	}
+
+	apiErr := &APIError{StatusCode: resp.StatusCode, Err: err}
+
+	// Some go-jira calls already read the body into a *jira.Error, others leave it to us
+	var jiraErr *jira.Error
+	if errors.As(err, &jiraErr) {
+		apiErr.Messages = jiraErr.ErrorMessages
+		apiErr.Fields = jiraErr.Errors
+	} else if resp.Body != nil {
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		var parsed struct {
+			ErrorMessages []string          `json:"errorMessages"`
+			Errors        map[string]string `json:"errors"`
+		}
+		if json.Unmarshal(body, &parsed) == nil {
+			apiErr.Messages = parsed.ErrorMessages
+			apiErr.Fields = parsed.Errors
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadRequest:
+		return &ValidationError{apiErr}
+	case http.StatusUnauthorized:
+		return &AuthError{apiErr}
+	case http.StatusForbidden:
+		return &PermissionError{apiErr}
+	case http.StatusNotFound:
+		return &NotFoundError{apiErr}
+	case http.StatusTooManyRequests:
+		return &RateLimitError{apiErr}
+	default:
+		return apiErr
+	}
+}
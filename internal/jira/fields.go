@@ -0,0 +1,153 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Custom field types Jira Software uses for the Epic Link and Sprint fields
+const (
+	epicLinkSchemaType = "com.pyxis.greenhopper.jira:gh-epic-link"
+	sprintSchemaType   = "com.pyxis.greenhopper.jira:gh-sprint"
+)
+
+// fieldCache is the on-disk record of field IDs discovered from a Jira instance. The cache
+// file holds one per instance URL, so profiles on different instances keep their own.
+type fieldCache struct {
+	EpicLinkField string    `json:"epic_link_field"`
+	SprintField   string    `json:"sprint_field,omitempty"`
+	Detected      time.Time `json:"detected"`
+}
+
+// GetEpicLinkField returns the ID of the Epic Link custom field. The configured value
+// wins; otherwise the field is discovered from Jira once and cached in the data directory.
+func (c *Client) GetEpicLinkField() (string, error) {
+	// Check if configured in config first
+	if c.config.Jira.EpicLinkField != "" {
+		return c.config.Jira.EpicLinkField, nil
+	}
+
+	if c.epicLinkResolved {
+		return c.epicLinkField, c.epicLinkErr
+	}
+	c.epicLinkResolved = true
+
+	if cache, ok := c.loadFieldCache(); ok {
+		c.epicLinkField = cache.EpicLinkField
+	} else {
+		c.epicLinkField, c.epicLinkErr = c.DetectEpicLinkField()
+	}
+
+	if c.epicLinkErr == nil && c.epicLinkField == "" {
+		c.epicLinkErr = fmt.Errorf("no Epic Link field found on %s (set jira.epic_link_field or run 'jai config detect')", c.config.Jira.URL)
+	}
+	return c.epicLinkField, c.epicLinkErr
+}
+
+// DetectEpicLinkField queries Jira's field metadata for the Epic Link field and refreshes
+// the cache. An empty ID without an error means the instance has no such field.
+func (c *Client) DetectEpicLinkField() (string, error) {
+	fields, resp, err := c.client.Field.GetList()
+	if err != nil {
+		return "", fmt.Errorf("failed to list Jira fields: %w", newAPIError(resp, err))
+	}
+	defer resp.Body.Close()
+
+	var fieldID, sprintField string
+	for _, field := range fields {
+		switch {
+		case field.Schema.Custom == epicLinkSchemaType:
+			fieldID = field.ID
+		case field.Schema.Custom == sprintSchemaType && sprintField == "":
+			sprintField = field.ID
+		// Fall back to the display name, which admins rarely rename
+		case fieldID == "" && field.Custom && field.Name == "Epic Link":
+			fieldID = field.ID
+		}
+	}
+
+	c.saveFieldCache(fieldCache{
+		EpicLinkField: fieldID,
+		SprintField:   sprintField,
+		Detected:      time.Now(),
+	})
+	c.sprintField = sprintField
+
+	return fieldID, nil
+}
+
+// GetSprintField returns the ID of the Sprint custom field, discovered from Jira along
+// with the Epic Link field. An empty ID means the instance has no Jira Software sprints;
+// that is cached too, so it isn't looked up again.
+func (c *Client) GetSprintField() string {
+	if c.sprintResolved {
+		return c.sprintField
+	}
+	c.sprintResolved = true
+
+	if cache, ok := c.loadFieldCache(); ok {
+		c.sprintField = cache.SprintField
+		return c.sprintField
+	}
+	if _, err := c.DetectEpicLinkField(); err != nil {
+		log.Printf("Warning: Failed to detect the Sprint field: %v", err)
+	}
+	return c.sprintField
+}
+
+// fieldCachePath returns where discovered field IDs are cached, or "" if there is no data directory
+func (c *Client) fieldCachePath() string {
+	if c.config.General.DataDir == "" {
+		return ""
+	}
+	return filepath.Join(c.config.General.DataDir, "jira_fields.json")
+}
+
+// readFieldCaches returns the cached field IDs of every instance, keyed by URL
+func (c *Client) readFieldCaches() map[string]fieldCache {
+	caches := make(map[string]fieldCache)
+	path := c.fieldCachePath()
+	if path == "" {
+		return caches
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return caches
+	}
+	// A cache in the older single-instance format doesn't parse and is simply rebuilt
+	if err := json.Unmarshal(data, &caches); err != nil {
+		return make(map[string]fieldCache)
+	}
+	return caches
+}
+
+// loadFieldCache returns the cached field IDs of the configured instance
+func (c *Client) loadFieldCache() (fieldCache, bool) {
+	cache, ok := c.readFieldCaches()[c.config.Jira.URL]
+	return cache, ok
+}
+
+// saveFieldCache records discovered field IDs for the configured instance in the data
+// directory. Failures only cost another lookup next time, so they are not reported.
+func (c *Client) saveFieldCache(cache fieldCache) {
+	path := c.fieldCachePath()
+	if path == "" {
+		return
+	}
+
+	caches := c.readFieldCaches()
+	caches[c.config.Jira.URL] = cache
+	data, err := json.MarshalIndent(caches, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,152 @@
+package jira
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// numericIDRe matches Jira's numeric object IDs, e.g. priority "3"
+var numericIDRe = regexp.MustCompile(`^\d+$`)
+
+// optionalFields are the issue fields jai can drop and retry without when Jira rejects them
+var optionalFields = map[string]func(fields *jira.IssueFields){
+	"priority":   func(fields *jira.IssueFields) { fields.Priority = nil },
+	"labels":     func(fields *jira.IssueFields) { fields.Labels = nil },
+	"components": func(fields *jira.IssueFields) { fields.Components = nil },
+	"assignee":   func(fields *jira.IssueFields) { fields.Assignee = nil },
+	"duedate":    func(fields *jira.IssueFields) { fields.Duedate = jira.Date{} },
+}
+
+// setOptionalFields maps priority, labels, components, assignee and due date onto an issue.
+// Values that can't be resolved are reported and left off rather than failing the create.
+func (c *Client) setOptionalFields(fields *jira.IssueFields, ticket *types.Ticket) {
+	if ticket.Priority != "" {
+		fields.Priority = priorityFor(ticket.Priority)
+	}
+
+	for _, label := range ticket.Labels {
+		// Jira labels can't contain spaces
+		label = strings.Join(strings.Fields(label), "-")
+		if label != "" {
+			fields.Labels = append(fields.Labels, label)
+		}
+	}
+
+	if len(ticket.Components) > 0 {
+		fields.Components = c.resolveComponents(ticket.Components)
+	}
+
+	if ticket.Assignee != "" {
+		assignee, err := c.resolveUser(ticket.Assignee)
+		if err != nil {
+			log.Printf("Warning: Not setting assignee: %v", err)
+		} else {
+			fields.Assignee = assignee
+		}
+	}
+
+	if ticket.DueDate != nil {
+		fields.Duedate = jira.Date(*ticket.DueDate)
+	}
+}
+
+// priorityFor builds a priority reference from either a priority name or its numeric ID
+func priorityFor(priority string) *jira.Priority {
+	if numericIDRe.MatchString(priority) {
+		return &jira.Priority{ID: priority}
+	}
+	return &jira.Priority{Name: priority}
+}
+
+// resolveComponents matches component names against the project, dropping unknown ones
+func (c *Client) resolveComponents(names []string) []*jira.Component {
+	info, err := c.getProjectInfo()
+	var components []*jira.Component
+	for _, name := range names {
+		if err != nil {
+			// Without the project's component list, let Jira validate
+			components = append(components, &jira.Component{Name: name})
+			continue
+		}
+
+		matched := ""
+		for _, known := range info.Components {
+			if strings.EqualFold(known, strings.TrimSpace(name)) {
+				matched = known
+				break
+			}
+		}
+		if matched == "" {
+			log.Printf("Warning: Not setting component %q: project %s has no such component (available: %s)",
+				name, c.config.Jira.Project, strings.Join(info.Components, ", "))
+			continue
+		}
+		components = append(components, &jira.Component{Name: matched})
+	}
+	return components
+}
+
+// resolveUser turns an email, username or display name into a Jira user reference.
+// Jira Cloud identifies users by accountId; Server and Data Center by username.
+func (c *Client) resolveUser(query string) (*jira.User, error) {
+	users, resp, err := c.client.User.Find(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up user %q: %w", query, newAPIError(resp, err))
+	}
+	defer resp.Body.Close()
+
+	var match *jira.User
+	for i, user := range users {
+		if strings.EqualFold(user.EmailAddress, query) || strings.EqualFold(user.Name, query) ||
+			strings.EqualFold(user.DisplayName, query) || user.AccountID == query {
+			match = &users[i]
+			break
+		}
+	}
+	if match == nil && len(users) == 1 {
+		match = &users[0]
+	}
+	if match == nil {
+		if len(users) == 0 {
+			return nil, fmt.Errorf("no Jira user matches %q", query)
+		}
+		return nil, fmt.Errorf("%q matches %d Jira users, use their email address", query, len(users))
+	}
+
+	if match.AccountID != "" {
+		return &jira.User{AccountID: match.AccountID}, nil
+	}
+	return &jira.User{Name: match.Name}, nil
+}
+
+// dropRejectedFields removes optional and custom fields Jira rejected with a validation error and
+// reports whether the request is worth retrying without them
+func dropRejectedFields(fields *jira.IssueFields, rejected map[string]string) bool {
+	if len(rejected) == 0 {
+		return false
+	}
+
+	// Only retry if every rejected field is one we can do without
+	for field := range rejected {
+		_, optional := optionalFields[field]
+		_, custom := fields.Unknowns[field]
+		if !optional && !custom {
+			return false
+		}
+	}
+
+	for field, message := range rejected {
+		log.Printf("Warning: Jira rejected %s (%s), creating the ticket without it", field, message)
+		if drop, ok := optionalFields[field]; ok {
+			drop(fields)
+		} else {
+			delete(fields.Unknowns, field)
+		}
+	}
+	return true
+}
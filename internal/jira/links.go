@@ -0,0 +1,79 @@
+package jira
+
+import (
+	"fmt"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// LinkType is an issue link type configured on the Jira instance, e.g.
+// "Blocks" with outward "blocks" and inward "is blocked by"
+type LinkType struct {
+	Name    string
+	Inward  string
+	Outward string
+}
+
+// GetLinkTypes returns the issue link types available on the Jira instance
+func (c *Client) GetLinkTypes() ([]LinkType, error) {
+	jiraTypes, resp, err := c.client.IssueLinkType.GetList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue link types: %w", newAPIError(resp, err))
+	}
+	defer resp.Body.Close()
+
+	var linkTypes []LinkType
+	for _, t := range jiraTypes {
+		linkTypes = append(linkTypes, LinkType{
+			Name:    t.Name,
+			Inward:  t.Inward,
+			Outward: t.Outward,
+		})
+	}
+	return linkTypes, nil
+}
+
+// LinkTickets links two tickets so that fromKey relates to toKey by the link type's
+// outward description, e.g. "SRE-1 blocks SRE-2"
+func (c *Client) LinkTickets(linkType, fromKey, toKey string) error {
+	// Jira reads a link as "inward issue <outward> outward issue"
+	link := &jira.IssueLink{
+		Type:         jira.IssueLinkType{Name: linkType},
+		InwardIssue:  &jira.Issue{Key: fromKey},
+		OutwardIssue: &jira.Issue{Key: toKey},
+	}
+
+	resp, err := c.client.Issue.AddLink(link)
+	if err != nil {
+		return fmt.Errorf("failed to link %s to %s: %w", fromKey, toKey, newAPIError(resp, err))
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// convertJiraLinks converts Jira issue links to links read from the owning ticket's side
+func convertJiraLinks(issueLinks []*jira.IssueLink) []types.Link {
+	var links []types.Link
+	for _, il := range issueLinks {
+		if il == nil {
+			continue
+		}
+
+		relation, other := il.Type.Outward, il.OutwardIssue
+		if other == nil {
+			relation, other = il.Type.Inward, il.InwardIssue
+		}
+		if other == nil {
+			continue
+		}
+
+		link := types.Link{Relation: relation, Key: other.Key}
+		if other.Fields != nil && other.Fields.Status != nil {
+			link.Status = other.Fields.Status.Name
+		}
+		links = append(links, link)
+	}
+	return links
+}
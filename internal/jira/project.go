@@ -0,0 +1,134 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Project styles accepted by jira.project_style
+const (
+	ProjectStyleAuto    = "auto"
+	ProjectStyleCompany = "company" // Company-managed (classic): epics linked through the Epic Link field
+	ProjectStyleTeam    = "team"    // Team-managed (next-gen): epics linked through the parent field
+)
+
+// projectInfo describes how a project models its issue hierarchy
+type projectInfo struct {
+	Style       string
+	EpicType    string
+	TaskType    string
+	SubtaskType string
+	Components  []string // Only known when the project could be read
+}
+
+// projectIssueType is an issue type as returned by the project endpoint
+type projectIssueType struct {
+	Name           string `json:"name"`
+	Subtask        bool   `json:"subtask"`
+	HierarchyLevel int    `json:"hierarchyLevel"`
+}
+
+// projectResponse is the subset of the project endpoint that go-jira doesn't model
+type projectResponse struct {
+	Key        string             `json:"key"`
+	Style      string             `json:"style"` // "classic" or "next-gen"
+	Simplified bool               `json:"simplified"`
+	IssueTypes []projectIssueType `json:"issueTypes"`
+	Components []struct {
+		Name string `json:"name"`
+	} `json:"components"`
+}
+
+// ProjectStyle returns whether the configured project is company- or team-managed
+func (c *Client) ProjectStyle() (string, error) {
+	info, err := c.getProjectInfo()
+	return info.Style, err
+}
+
+// getProjectInfo resolves the project style and issue type names once per client. On
+// failure it still returns the company-managed defaults alongside the error.
+func (c *Client) getProjectInfo() (*projectInfo, error) {
+	if c.project != nil {
+		return c.project, c.projectErr
+	}
+
+	info := &projectInfo{
+		Style:       ProjectStyleCompany,
+		EpicType:    "Epic",
+		TaskType:    "Task",
+		SubtaskType: "Sub-task",
+	}
+	c.project = info
+
+	configured := strings.ToLower(c.config.Jira.ProjectStyle)
+	if configured == ProjectStyleCompany || configured == ProjectStyleTeam {
+		info.Style = configured
+	}
+
+	project, err := c.fetchProject(c.config.Jira.Project)
+	if err != nil {
+		c.projectErr = err
+		return info, err
+	}
+
+	if configured != ProjectStyleCompany && configured != ProjectStyleTeam {
+		if project.Simplified || project.Style == "next-gen" {
+			info.Style = ProjectStyleTeam
+		}
+	}
+
+	for _, component := range project.Components {
+		info.Components = append(info.Components, component.Name)
+	}
+
+	for _, issueType := range project.IssueTypes {
+		switch {
+		case issueType.Subtask:
+			// Prefer the stock names over custom subtask types
+			if !hasIssueType(project.IssueTypes, info.SubtaskType) || issueType.Name == "Subtask" || issueType.Name == "Sub-task" {
+				info.SubtaskType = issueType.Name
+			}
+		case issueType.Name == "Epic" || issueType.HierarchyLevel == 1:
+			if !hasIssueType(project.IssueTypes, info.EpicType) {
+				info.EpicType = issueType.Name
+			}
+		case issueType.HierarchyLevel == 0:
+			if !hasIssueType(project.IssueTypes, info.TaskType) {
+				info.TaskType = issueType.Name
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// fetchProject reads the project's style and issue types from Jira
+func (c *Client) fetchProject(key string) (*projectResponse, error) {
+	if key == "" {
+		return nil, fmt.Errorf("no Jira project configured")
+	}
+
+	req, err := c.client.NewRequest("GET", fmt.Sprintf("rest/api/2/project/%s", key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build project request: %w", err)
+	}
+
+	project := &projectResponse{}
+	resp, err := c.client.Do(req, project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Jira project %s: %w", key, newAPIError(resp, err))
+	}
+	defer resp.Body.Close()
+
+	return project, nil
+}
+
+// hasIssueType reports whether name is one of the project's issue types
+func hasIssueType(issueTypes []projectIssueType, name string) bool {
+	for _, issueType := range issueTypes {
+		if issueType.Name == name {
+			return true
+		}
+	}
+	return false
+}
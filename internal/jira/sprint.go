@@ -0,0 +1,171 @@
+package jira
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// Sprint states reported by the Agile API
+const (
+	SprintActive = "active"
+	SprintFuture = "future"
+	SprintClosed = "closed"
+)
+
+// legacySprintNameRe extracts the name from the string form Jira Server uses for sprint
+// field values, e.g. "com.atlassian.greenhopper.service.sprint.Sprint@1f[id=4,state=ACTIVE,name=Sprint 4,...]"
+var legacySprintNameRe = regexp.MustCompile(`name=([^,\]]*)`)
+
+// Sprint is a sprint on the configured board
+type Sprint struct {
+	ID        int
+	Name      string
+	State     string
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// GetSprints returns the board's sprints in the given states ("active", "future", "closed"),
+// in the order the board lists them
+func (c *Client) GetSprints(states ...string) ([]Sprint, error) {
+	if c.config.Jira.BoardID == 0 {
+		return nil, fmt.Errorf("no board configured (set jira.board_id)")
+	}
+
+	var sprints []Sprint
+	startAt := 0
+	for {
+		list, resp, err := c.client.Board.GetAllSprintsWithOptions(c.config.Jira.BoardID, &jira.GetAllSprintsOptions{
+			State:         strings.Join(states, ","),
+			SearchOptions: jira.SearchOptions{StartAt: startAt},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sprints for board %d: %w", c.config.Jira.BoardID, newAPIError(resp, err))
+		}
+		resp.Body.Close()
+
+		for _, s := range list.Values {
+			sprints = append(sprints, Sprint{
+				ID:        s.ID,
+				Name:      s.Name,
+				State:     s.State,
+				StartDate: s.StartDate,
+				EndDate:   s.EndDate,
+			})
+		}
+
+		startAt += len(list.Values)
+		if list.IsLast || len(list.Values) == 0 {
+			break
+		}
+	}
+
+	return sprints, nil
+}
+
+// FindSprint resolves "active", "next" or a sprint name to one of the board's open sprints
+func (c *Client) FindSprint(query string) (*Sprint, error) {
+	sprints, err := c.GetSprints(SprintActive, SprintFuture)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := strings.ToLower(strings.TrimSpace(query))
+	switch normalized {
+	case "active", "current":
+		for i, s := range sprints {
+			if s.State == SprintActive {
+				return &sprints[i], nil
+			}
+		}
+		return nil, fmt.Errorf("board %d has no active sprint", c.config.Jira.BoardID)
+	case "next":
+		// Future sprints are listed in the order they are planned on the board
+		for i, s := range sprints {
+			if s.State == SprintFuture {
+				return &sprints[i], nil
+			}
+		}
+		return nil, fmt.Errorf("board %d has no future sprint", c.config.Jira.BoardID)
+	}
+
+	for i, s := range sprints {
+		if strings.ToLower(s.Name) == normalized {
+			return &sprints[i], nil
+		}
+	}
+
+	// Fall back to substring matching, but only accept an unambiguous result
+	var matches []*Sprint
+	var names []string
+	for i, s := range sprints {
+		names = append(names, fmt.Sprintf("%q", s.Name))
+		if strings.Contains(strings.ToLower(s.Name), normalized) {
+			matches = append(matches, &sprints[i])
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("%q matches more than one sprint, open sprints: %s", query, strings.Join(names, ", "))
+	}
+	return nil, fmt.Errorf("no open sprint matches %q, open sprints: %s", query, strings.Join(names, ", "))
+}
+
+// MoveToSprint moves tickets into a sprint. Subtasks can't be moved on their own; they
+// follow their parent.
+func (c *Client) MoveToSprint(sprintID int, keys ...string) error {
+	resp, err := c.client.Sprint.MoveIssuesToSprint(sprintID, keys)
+	if err != nil {
+		return fmt.Errorf("failed to move %s to sprint %d: %w", strings.Join(keys, ", "), sprintID, newAPIError(resp, err))
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// GetSprintTickets returns every ticket in a sprint
+func (c *Client) GetSprintTickets(sprintID int) ([]*types.Ticket, error) {
+	return c.SearchAll(fmt.Sprintf("sprint = %d ORDER BY rank", sprintID), SearchOptions{})
+}
+
+// sprintName picks the sprint a ticket is planned in from the value of the Sprint field:
+// the active sprint if there is one, otherwise the most recent. Jira Cloud returns sprint
+// objects, while Jira Server may return them in a legacy string form.
+func sprintName(value interface{}) string {
+	values, ok := value.([]interface{})
+	if !ok {
+		return ""
+	}
+
+	var name string
+	for _, v := range values {
+		var sprintName, state string
+		switch sprint := v.(type) {
+		case map[string]interface{}:
+			sprintName, _ = sprint["name"].(string)
+			state, _ = sprint["state"].(string)
+		case string:
+			if m := legacySprintNameRe.FindStringSubmatch(sprint); m != nil {
+				sprintName = m[1]
+			}
+			if strings.Contains(sprint, "state=ACTIVE") {
+				state = SprintActive
+			}
+		}
+		if sprintName == "" {
+			continue
+		}
+		name = sprintName
+		if strings.EqualFold(state, SprintActive) {
+			break
+		}
+	}
+	return name
+}
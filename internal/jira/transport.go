@@ -0,0 +1,170 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Defaults for jira.timeout and jira.max_retries
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultMaxRetries = 3
+)
+
+// Backoff between retries starts at retryBaseDelay and doubles up to retryMaxDelay.
+// Rate limits asking for a longer wait than maxRetryAfter are reported instead.
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+	maxRetryAfter  = time.Minute
+)
+
+// retryTransport retries failed Jira requests with exponential backoff and puts a
+// deadline on every attempt. Rate limited requests (429) are retried after the
+// Retry-After delay Jira asks for. Server errors and network failures are only
+// retried for idempotent methods, so a create is never sent twice.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	timeout    time.Duration
+}
+
+// newRetryTransport creates a retrying transport on top of the default transport
+func newRetryTransport(timeout time.Duration, maxRetries int) *retryTransport {
+	return &retryTransport{
+		base:       http.DefaultTransport,
+		maxRetries: maxRetries,
+		timeout:    timeout,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		ctx, cancel := req.Context(), context.CancelFunc(func() {})
+		if t.timeout > 0 {
+			ctx, cancel = context.WithTimeout(req.Context(), t.timeout)
+		}
+		resp, err := t.base.RoundTrip(attemptReq.WithContext(ctx))
+
+		wait, retry := t.retryDelay(req, resp, err, attempt)
+		if !retry {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			// The deadline covers reading the body too, so release it only once that's done
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		reason := "network error"
+		if errors.Is(err, context.DeadlineExceeded) {
+			reason = "timed out"
+		}
+		if resp != nil {
+			reason = resp.Status
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		cancel()
+
+		log.Printf("Warning: Jira request %s %s failed (%s), retrying in %s", req.Method, req.URL.Path, reason, wait.Round(time.Millisecond))
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// retryDelay decides whether an attempt should be retried and how long to wait first
+func (t *retryTransport) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= t.maxRetries || req.Context().Err() != nil {
+		return 0, false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body can't be sent again
+		return 0, false
+	}
+
+	switch {
+	case err != nil:
+		return backoff(attempt), isIdempotent(req.Method)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			// Don't hang the CLI for a long rate limit window, report it instead
+			return wait, wait <= maxRetryAfter
+		}
+		return backoff(attempt), true
+	case resp.StatusCode >= 500:
+		return backoff(attempt), isIdempotent(req.Method)
+	}
+	return 0, false
+}
+
+// backoff returns the exponential delay before retry number attempt, with jitter
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay << uint(attempt)
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		wait := time.Until(at)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// isIdempotent reports whether a request with this method can safely be sent twice
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// cancelOnClose releases a request's context once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,95 @@
+package jira
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// Webhook events handled by jai, as sent in the payload's webhookEvent field
+const (
+	EventIssueCreated   = "jira:issue_created"
+	EventIssueUpdated   = "jira:issue_updated"
+	EventCommentCreated = "comment_created"
+)
+
+// WebhookEvent is a Jira webhook payload converted to our types
+type WebhookEvent struct {
+	Event   string
+	Ticket  *types.Ticket
+	Comment *types.Comment // Set for comment events
+}
+
+// webhookPayload is the part of a Jira webhook body jai reads
+type webhookPayload struct {
+	WebhookEvent string        `json:"webhookEvent"`
+	Issue        *jira.Issue   `json:"issue"`
+	Comment      *jira.Comment `json:"comment"`
+}
+
+// ParseWebhook decodes a webhook body and converts its issue the same way tickets fetched
+// from the API are. Comment events only carry a few of the issue's fields, so fields the
+// payload leaves out are left empty on the ticket.
+func (c *Client) ParseWebhook(body []byte) (*WebhookEvent, error) {
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+	if payload.Issue == nil || payload.Issue.Key == "" {
+		return nil, fmt.Errorf("webhook payload for %q has no issue", payload.WebhookEvent)
+	}
+	if payload.Issue.Fields == nil {
+		payload.Issue.Fields = &jira.IssueFields{}
+	}
+	if payload.Issue.Fields.Status == nil {
+		payload.Issue.Fields.Status = &jira.Status{}
+	}
+	if payload.Issue.Fields.Type.Name == "" && payload.Issue.Fields.Parent != nil {
+		// Partial payloads may leave out the type, but a parent is enough to place a subtask
+		payload.Issue.Fields.Type.Subtask = true
+	}
+
+	event := &WebhookEvent{
+		Event:  payload.WebhookEvent,
+		Ticket: c.convertJiraIssue(payload.Issue),
+	}
+	if payload.Comment != nil {
+		if comments := convertJiraComments([]*jira.Comment{payload.Comment}); len(comments) > 0 {
+			event.Comment = &comments[0]
+		}
+	}
+	return event, nil
+}
+
+// VerifyWebhook checks a webhook request against the shared secret. Jira Cloud signs the
+// body with the secret and sends the HMAC in X-Hub-Signature; webhooks that can't be signed
+// (Jira Server, automation rules) pass the secret in the URL as ?secret=... instead.
+func VerifyWebhook(r *http.Request, body []byte, secret string) bool {
+	if secret == "" {
+		return false
+	}
+
+	if signature := r.Header.Get("X-Hub-Signature"); signature != "" {
+		method, digest, ok := strings.Cut(signature, "=")
+		if !ok || method != "sha256" {
+			return false
+		}
+		got, err := hex.DecodeString(digest)
+		if err != nil {
+			return false
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		return hmac.Equal(got, mac.Sum(nil))
+	}
+
+	return subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("secret")), []byte(secret)) == 1
+}
@@ -0,0 +1,581 @@
+package linear
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jai/internal/tracker"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// DefaultURL is the Linear GraphQL endpoint used when tracker.linear.url is not set
+const DefaultURL = "https://api.linear.app/graphql"
+
+// ProjectPrefix is the prefix of the keys given to projects, which stand in for epics, e.g.
+// LP-3. Issues keep their Linear identifiers, e.g. ENG-123.
+const ProjectPrefix = "LP"
+
+const (
+	// searchPageSize is the number of issues requested per page
+	searchPageSize = 100
+
+	// maxSearchResults caps the issues returned by a single search
+	maxSearchResults = 1000
+)
+
+var _ tracker.Backend = (*Client)(nil)
+
+// Client handles Linear GraphQL API interactions. Epics are projects, tasks are issues in
+// the configured team and project, and subtasks are sub-issues of their task.
+type Client struct {
+	api      *tracker.APIClient
+	team     string // Team key, e.g. ENG
+	keysPath string
+
+	// teamID is looked up from the team key on first use
+	teamID string
+}
+
+// NewClient creates a new Linear client for the configured team
+func NewClient(config *types.Config) (*Client, error) {
+	team := strings.ToUpper(strings.TrimSpace(config.Tracker.Linear.Team))
+	if team == "" {
+		return nil, fmt.Errorf("tracker.linear.team must be set to a team key, e.g. ENG")
+	}
+	// Issue identifiers must look like PROJ-123 for the markdown headers to pick them up
+	if strings.Trim(team, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return nil, fmt.Errorf("tracker.linear.team must only contain letters, got %q", team)
+	}
+	if team == ProjectPrefix {
+		return nil, fmt.Errorf("tracker.linear.team %s clashes with the %s-<n> keys given to projects", team, ProjectPrefix)
+	}
+
+	endpoint := config.Tracker.Linear.URL
+	if endpoint == "" {
+		endpoint = DefaultURL
+	}
+
+	header := http.Header{}
+	if token := config.Tracker.Linear.Token; token != "" {
+		// Personal API keys are sent as is, OAuth tokens with their Bearer prefix
+		header.Set("Authorization", token)
+	}
+
+	return &Client{
+		api:      tracker.NewAPIClient("Linear", endpoint, header, tracker.GraphQLErrorDetails),
+		team:     team,
+		keysPath: filepath.Join(config.General.DataDir, "linear_projects.json"),
+	}, nil
+}
+
+// issue is a Linear issue as returned by the GraphQL API
+type issue struct {
+	ID            string    `json:"id"`
+	Identifier    string    `json:"identifier"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	PriorityLabel string    `json:"priorityLabel"`
+	DueDate       string    `json:"dueDate"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	State         *struct {
+		Name string `json:"name"`
+	} `json:"state"`
+	Assignee *struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"assignee"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Project *struct {
+		ID string `json:"id"`
+	} `json:"project"`
+	Parent *struct {
+		Identifier string `json:"identifier"`
+	} `json:"parent"`
+}
+
+// issueFields are the fields queried for every issue
+const issueFields = `id identifier title description priorityLabel dueDate createdAt updatedAt
+	state { name } assignee { name email } labels { nodes { name } } project { id } parent { identifier }`
+
+// project is a Linear project as returned by the GraphQL API
+type project struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Content    string    `json:"content"`
+	State      string    `json:"state"`
+	TargetDate string    `json:"targetDate"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// projectFields are the fields queried for every project
+const projectFields = `id name content state targetDate createdAt updatedAt`
+
+// workflowState is a state of the team's issue workflow
+type workflowState struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"` // backlog, unstarted, started, completed or canceled
+}
+
+// CreateTicket creates a project for an epic, an issue for a task or a sub-issue for a
+// subtask
+func (c *Client) CreateTicket(ticket *types.Ticket) (*types.Ticket, error) {
+	teamID, err := c.resolveTeamID()
+	if err != nil {
+		return nil, err
+	}
+	created := *ticket
+
+	if ticket.Type == types.TicketTypeEpic {
+		input := map[string]interface{}{
+			"name":    ticket.Title,
+			"content": tracker.Description(ticket),
+			"teamIds": []string{teamID},
+		}
+		if ticket.DueDate != nil {
+			input["targetDate"] = ticket.DueDate.Format(tracker.DateFormat)
+		}
+
+		var result struct {
+			ProjectCreate struct {
+				Project project `json:"project"`
+			} `json:"projectCreate"`
+		}
+		query := `mutation($input: ProjectCreateInput!) { projectCreate(input: $input) { project { ` + projectFields + ` } } }`
+		if err := c.api.GraphQL(query, map[string]interface{}{"input": input}, &result); err != nil {
+			return nil, fmt.Errorf("failed to create Linear project: %w", err)
+		}
+		p := result.ProjectCreate.Project
+		key, err := c.projectKey(p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("created Linear project %q but failed to give it a key: %w", p.Name, err)
+		}
+		created.Key = key
+		created.ID = p.ID
+		created.Status = p.State
+		created.Created = time.Now()
+		created.Updated = time.Now()
+		return &created, nil
+	}
+
+	input := map[string]interface{}{
+		"teamId":      teamID,
+		"title":       ticket.Title,
+		"description": tracker.Description(ticket),
+	}
+	if ticket.DueDate != nil {
+		input["dueDate"] = ticket.DueDate.Format(tracker.DateFormat)
+	}
+	if priority, ok := priorityValue(ticket.Priority); ok {
+		input["priority"] = priority
+	}
+	if ticket.EpicKey != "" && isProjectKey(ticket.EpicKey) {
+		projectID, err := c.projectID(ticket.EpicKey)
+		if err != nil {
+			return nil, err
+		}
+		input["projectId"] = projectID
+	}
+	if ticket.Type == types.TicketTypeSubtask && ticket.ParentKey != "" {
+		parent, err := c.getIssue(ticket.ParentKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find parent %s: %w", ticket.ParentKey, err)
+		}
+		input["parentId"] = parent.ID
+	}
+
+	var result struct {
+		IssueCreate struct {
+			Issue issue `json:"issue"`
+		} `json:"issueCreate"`
+	}
+	query := `mutation($input: IssueCreateInput!) { issueCreate(input: $input) { issue { ` + issueFields + ` } } }`
+	if err := c.api.GraphQL(query, map[string]interface{}{"input": input}, &result); err != nil {
+		return nil, fmt.Errorf("failed to create Linear issue: %w", err)
+	}
+	is := result.IssueCreate.Issue
+	created.Key = is.Identifier
+	created.ID = is.ID
+	if is.State != nil {
+		created.Status = is.State.Name
+	}
+	created.Created = time.Now()
+	created.Updated = time.Now()
+	return &created, nil
+}
+
+// GetTicket retrieves an issue by identifier or a project by key
+func (c *Client) GetTicket(key string) (*types.Ticket, error) {
+	if isProjectKey(key) {
+		p, err := c.getProject(key)
+		if err != nil {
+			return nil, err
+		}
+		return convertProject(p, key), nil
+	}
+
+	is, err := c.getIssue(key)
+	if err != nil {
+		return nil, err
+	}
+	keys := c.issueProjectKeys()
+	ticket := convertIssue(is, keys)
+	c.saveNewProjectKeys(keys)
+	return ticket, nil
+}
+
+// UpdateTicket updates the title and description of an issue or project
+func (c *Client) UpdateTicket(ticket *types.Ticket) error {
+	if isProjectKey(ticket.Key) {
+		id, err := c.projectID(ticket.Key)
+		if err != nil {
+			return err
+		}
+		input := map[string]interface{}{"name": ticket.Title}
+		if desc := tracker.Description(ticket); desc != "" {
+			input["content"] = desc
+		}
+		query := `mutation($id: String!, $input: ProjectUpdateInput!) { projectUpdate(id: $id, input: $input) { success } }`
+		if err := c.api.GraphQL(query, map[string]interface{}{"id": id, "input": input}, nil); err != nil {
+			return fmt.Errorf("failed to update Linear project %s: %w", ticket.Key, err)
+		}
+		return nil
+	}
+
+	is, err := c.getIssue(ticket.Key)
+	if err != nil {
+		return err
+	}
+	input := map[string]interface{}{"title": ticket.Title}
+	if desc := tracker.Description(ticket); desc != "" {
+		input["description"] = desc
+	}
+	if priority, ok := priorityValue(ticket.Priority); ok {
+		input["priority"] = priority
+	}
+	if err := c.updateIssue(is.ID, input); err != nil {
+		return fmt.Errorf("failed to update Linear issue %s: %w", ticket.Key, err)
+	}
+	return nil
+}
+
+// SearchTickets returns the team's issues whose title or description contain the query. An
+// empty query returns every issue of the team.
+func (c *Client) SearchTickets(query string) ([]*types.Ticket, error) {
+	filter := map[string]interface{}{
+		"team": map[string]interface{}{"key": map[string]interface{}{"eq": c.team}},
+	}
+	if query = strings.TrimSpace(query); query != "" {
+		filter["or"] = []interface{}{
+			map[string]interface{}{"title": map[string]interface{}{"containsIgnoreCase": query}},
+			map[string]interface{}{"description": map[string]interface{}{"containsIgnoreCase": query}},
+		}
+	}
+
+	gql := `query($filter: IssueFilter, $first: Int, $after: String) {
+		issues(filter: $filter, first: $first, after: $after) {
+			nodes { ` + issueFields + ` }
+			pageInfo { hasNextPage endCursor }
+		}
+	}`
+
+	// The project key map is read once and saved once for every issue found
+	keys := c.issueProjectKeys()
+	defer c.saveNewProjectKeys(keys)
+
+	var tickets []*types.Ticket
+	var after interface{}
+	for {
+		var result struct {
+			Issues struct {
+				Nodes    []issue `json:"nodes"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"issues"`
+		}
+		variables := map[string]interface{}{"filter": filter, "first": searchPageSize, "after": after}
+		if err := c.api.GraphQL(gql, variables, &result); err != nil {
+			return nil, fmt.Errorf("failed to search Linear issues: %w", err)
+		}
+
+		for i := range result.Issues.Nodes {
+			tickets = append(tickets, convertIssue(&result.Issues.Nodes[i], keys))
+		}
+		if !result.Issues.PageInfo.HasNextPage || len(tickets) >= maxSearchResults {
+			break
+		}
+		after = result.Issues.PageInfo.EndCursor
+	}
+	return tickets, nil
+}
+
+// Transition moves an issue to the team's workflow state best matching the status. Projects
+// are completed for statuses like Done or Closed and started otherwise.
+func (c *Client) Transition(key, status string) (string, error) {
+	if isProjectKey(key) {
+		id, err := c.projectID(key)
+		if err != nil {
+			return "", err
+		}
+		state := "started"
+		if tracker.IsClosedStatus(status) {
+			state = "completed"
+		}
+		query := `mutation($id: String!, $input: ProjectUpdateInput!) { projectUpdate(id: $id, input: $input) { success } }`
+		if err := c.api.GraphQL(query, map[string]interface{}{"id": id, "input": map[string]interface{}{"state": state}}, nil); err != nil {
+			return "", fmt.Errorf("failed to transition %s: %w", key, err)
+		}
+		return state, nil
+	}
+
+	is, err := c.getIssue(key)
+	if err != nil {
+		return "", err
+	}
+	states, err := c.workflowStates()
+	if err != nil {
+		return "", err
+	}
+	state, err := matchState(states, status)
+	if err != nil {
+		return "", fmt.Errorf("cannot move %s to %q: %w", key, status, err)
+	}
+	if is.State != nil && is.State.Name == state.Name {
+		return state.Name, nil
+	}
+	if err := c.updateIssue(is.ID, map[string]interface{}{"stateId": state.ID}); err != nil {
+		return "", fmt.Errorf("failed to transition %s: %w", key, err)
+	}
+	return state.Name, nil
+}
+
+// getIssue retrieves an issue by identifier, e.g. ENG-123
+func (c *Client) getIssue(key string) (*issue, error) {
+	var result struct {
+		Issue *issue `json:"issue"`
+	}
+	query := `query($id: String!) { issue(id: $id) { ` + issueFields + ` } }`
+	if err := c.api.GraphQL(query, map[string]interface{}{"id": strings.ToUpper(key)}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get Linear issue %s: %w", key, err)
+	}
+	if result.Issue == nil {
+		return nil, fmt.Errorf("Linear issue %s not found", key)
+	}
+	return result.Issue, nil
+}
+
+// getProject retrieves a project by key
+func (c *Client) getProject(key string) (*project, error) {
+	id, err := c.projectID(key)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Project *project `json:"project"`
+	}
+	query := `query($id: String!) { project(id: $id) { ` + projectFields + ` } }`
+	if err := c.api.GraphQL(query, map[string]interface{}{"id": id}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get Linear project %s: %w", key, err)
+	}
+	if result.Project == nil {
+		return nil, fmt.Errorf("Linear project %s not found", key)
+	}
+	return result.Project, nil
+}
+
+// updateIssue applies an IssueUpdateInput to an issue
+func (c *Client) updateIssue(id string, input map[string]interface{}) error {
+	query := `mutation($id: String!, $input: IssueUpdateInput!) { issueUpdate(id: $id, input: $input) { success } }`
+	return c.api.GraphQL(query, map[string]interface{}{"id": id, "input": input}, nil)
+}
+
+// resolveTeamID looks up the ID of the configured team
+func (c *Client) resolveTeamID() (string, error) {
+	if c.teamID != "" {
+		return c.teamID, nil
+	}
+	var result struct {
+		Teams struct {
+			Nodes []struct {
+				ID string `json:"id"`
+			} `json:"nodes"`
+		} `json:"teams"`
+	}
+	query := `query($key: String!) { teams(filter: { key: { eq: $key } }) { nodes { id } } }`
+	if err := c.api.GraphQL(query, map[string]interface{}{"key": c.team}, &result); err != nil {
+		return "", fmt.Errorf("failed to look up Linear team %s: %w", c.team, err)
+	}
+	if len(result.Teams.Nodes) == 0 {
+		return "", fmt.Errorf("no Linear team with key %s", c.team)
+	}
+	c.teamID = result.Teams.Nodes[0].ID
+	return c.teamID, nil
+}
+
+// workflowStates returns the states of the team's issue workflow
+func (c *Client) workflowStates() ([]workflowState, error) {
+	teamID, err := c.resolveTeamID()
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Team struct {
+			States struct {
+				Nodes []workflowState `json:"nodes"`
+			} `json:"states"`
+		} `json:"team"`
+	}
+	query := `query($id: String!) { team(id: $id) { states { nodes { id name type } } } }`
+	if err := c.api.GraphQL(query, map[string]interface{}{"id": teamID}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get workflow states of %s: %w", c.team, err)
+	}
+	return result.Team.States.Nodes, nil
+}
+
+// matchState picks the workflow state for a status: an exact name match, then the only
+// state whose name contains it, then for statuses like Done the team's completed state
+func matchState(states []workflowState, status string) (*workflowState, error) {
+	want := tracker.NormalizeStatus(status)
+	if want == "" {
+		return nil, fmt.Errorf("no status given")
+	}
+
+	var partial []*workflowState
+	for i := range states {
+		name := tracker.NormalizeStatus(states[i].Name)
+		if name == want {
+			return &states[i], nil
+		}
+		if strings.Contains(name, want) {
+			partial = append(partial, &states[i])
+		}
+	}
+	if len(partial) == 1 {
+		return partial[0], nil
+	}
+	if len(partial) == 0 && tracker.IsClosedStatus(status) {
+		for i := range states {
+			if states[i].Type == "completed" {
+				return &states[i], nil
+			}
+		}
+	}
+
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = state.Name
+	}
+	if len(partial) > 1 {
+		return nil, fmt.Errorf("status is ambiguous (available: %s)", strings.Join(names, ", "))
+	}
+	return nil, fmt.Errorf("no matching workflow state (available: %s)", strings.Join(names, ", "))
+}
+
+// convertIssue converts a Linear issue to our Ticket type. Sub-issues are subtasks and
+// the issue's project, given a key in keys if it has none yet, is its epic. Without keys
+// the epic is left unset.
+func convertIssue(is *issue, keys *projectKeys) *types.Ticket {
+	ticket := &types.Ticket{
+		Key:         is.Identifier,
+		ID:          is.ID,
+		Type:        types.TicketTypeTask,
+		Title:       is.Title,
+		Description: is.Description,
+		Priority:    is.PriorityLabel,
+		DueDate:     tracker.ParseDate(is.DueDate),
+		Created:     is.CreatedAt,
+		Updated:     is.UpdatedAt,
+	}
+	if ticket.Priority == "No priority" {
+		ticket.Priority = ""
+	}
+	if is.State != nil {
+		ticket.Status = is.State.Name
+	}
+	if is.Assignee != nil {
+		ticket.Assignee = is.Assignee.Email
+		if ticket.Assignee == "" {
+			ticket.Assignee = is.Assignee.Name
+		}
+	}
+	for _, l := range is.Labels.Nodes {
+		ticket.Labels = append(ticket.Labels, l.Name)
+	}
+	if is.Parent != nil {
+		ticket.Type = types.TicketTypeSubtask
+		ticket.ParentKey = is.Parent.Identifier
+	}
+	if is.Project != nil && keys != nil {
+		ticket.EpicKey = keys.keyFor(is.Project.ID)
+	}
+	return ticket
+}
+
+// issueProjectKeys loads the project key map for converting issues, or returns nil, leaving
+// their epics unset, if it can't be read
+func (c *Client) issueProjectKeys() *projectKeys {
+	keys, err := c.loadProjectKeys()
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return nil
+	}
+	return keys
+}
+
+// saveNewProjectKeys saves the project key map if converting issues handed out new keys
+func (c *Client) saveNewProjectKeys(keys *projectKeys) {
+	if keys == nil || !keys.changed {
+		return
+	}
+	if err := c.saveProjectKeys(keys); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// convertProject converts a Linear project to an epic
+func convertProject(p *project, key string) *types.Ticket {
+	return &types.Ticket{
+		Key:         key,
+		ID:          p.ID,
+		Type:        types.TicketTypeEpic,
+		Title:       p.Name,
+		Description: p.Content,
+		Status:      p.State,
+		DueDate:     tracker.ParseDate(p.TargetDate),
+		Created:     p.CreatedAt,
+		Updated:     p.UpdatedAt,
+	}
+}
+
+// isProjectKey reports whether a key is one given to a project, e.g. LP-3
+func isProjectKey(key string) bool {
+	prefix, _, ok := strings.Cut(strings.ToUpper(key), "-")
+	return ok && prefix == ProjectPrefix
+}
+
+// priorityValue maps a priority name to Linear's 1 (urgent) to 4 (low) scale
+func priorityValue(priority string) (int, bool) {
+	switch tracker.NormalizeStatus(priority) {
+	case "urgent", "highest", "critical", "blocker":
+		return 1, true
+	case "high":
+		return 2, true
+	case "medium", "normal":
+		return 3, true
+	case "low", "lowest":
+		return 4, true
+	}
+	return 0, false
+}
@@ -0,0 +1,357 @@
+package linear
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jai/internal/tracker/trackertest"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// operations are the top-level fields the client queries, most specific first
+var operations = []string{"projectCreate", "projectUpdate", "issueCreate", "issueUpdate", "teams", "team", "issues", "issue", "project"}
+
+// operation returns the top-level field a GraphQL request asks for
+func operation(r trackertest.Request) string {
+	query, _ := r.GraphQL()
+	for _, name := range operations {
+		if strings.Contains(query, name+"(") {
+			return name
+		}
+	}
+	return ""
+}
+
+// newTestClient starts a recording server that answers every GraphQL request with handler,
+// keyed by its top-level field, and returns a client for the ENG team on it
+func newTestClient(t *testing.T, handler func(w http.ResponseWriter, op string, r trackertest.Request)) (*Client, *[]trackertest.Request, string) {
+	t.Helper()
+	serverURL, requests := trackertest.NewServer(t, func(w http.ResponseWriter, r trackertest.Request) {
+		// Personal API keys are sent without a Bearer prefix
+		if got := r.Header.Get("Authorization"); got != "lin_api_secret" {
+			t.Errorf("%s: Authorization = %q", operation(r), got)
+		}
+		if op := operation(r); op == "teams" {
+			trackertest.Reply(w, http.StatusOK, `{"data": {"teams": {"nodes": [{"id": "team-1"}]}}}`)
+		} else {
+			handler(w, op, r)
+		}
+	})
+
+	dataDir := t.TempDir()
+	config := &types.Config{}
+	config.General.DataDir = dataDir
+	config.Tracker.Linear.URL = serverURL
+	config.Tracker.Linear.Team = "eng"
+	config.Tracker.Linear.Token = "lin_api_secret"
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, requests, dataDir
+}
+
+// variables returns the variables of the last request for a top-level field
+func variables(t *testing.T, requests []trackertest.Request, op string) map[string]interface{} {
+	t.Helper()
+	for i := len(requests) - 1; i >= 0; i-- {
+		if operation(requests[i]) == op {
+			_, vars := requests[i].GraphQL()
+			return vars
+		}
+	}
+	t.Fatalf("no %s request sent", op)
+	return nil
+}
+
+// readProjectKeys reads linear_projects.json from a data directory
+func readProjectKeys(t *testing.T, dataDir string) map[string]string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dataDir, "linear_projects.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys projectKeys
+	if err := json.Unmarshal(data, &keys); err != nil {
+		t.Fatal(err)
+	}
+	return keys.Projects
+}
+
+func TestNewClientRejectsBadTeams(t *testing.T) {
+	for _, team := range []string{"", "ENG2", "LP"} {
+		config := &types.Config{}
+		config.Tracker.Linear.Team = team
+		if _, err := NewClient(config); err == nil {
+			t.Errorf("NewClient accepted team %q", team)
+		}
+	}
+}
+
+func TestCreateProjectsHandsOutKeys(t *testing.T) {
+	var created int
+	client, requests, dataDir := newTestClient(t, func(w http.ResponseWriter, op string, r trackertest.Request) {
+		created++
+		trackertest.Reply(w, http.StatusOK, fmt.Sprintf(`{"data": {"projectCreate": {"project": {"id": "proj-%d", "name": "Tracing", "state": "planned"}}}}`, created))
+	})
+
+	first, err := client.CreateTicket(&types.Ticket{Type: types.TicketTypeEpic, Title: "Tracing", RawContent: " Roll out tracing "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.CreateTicket(&types.Ticket{Type: types.TicketTypeEpic, Title: "Metrics"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Key != "LP-1" || first.ID != "proj-1" || first.Status != "planned" || second.Key != "LP-2" {
+		t.Errorf("created %s (%s, %s) and %s, want LP-1 (proj-1, planned) and LP-2", first.Key, first.ID, first.Status, second.Key)
+	}
+
+	input, _ := variables(t, *requests, "projectCreate")["input"].(map[string]interface{})
+	if teams, _ := input["teamIds"].([]interface{}); input["name"] != "Metrics" || len(teams) != 1 || teams[0] != "team-1" {
+		t.Errorf("projectCreate input = %v, want Metrics in team-1", input)
+	}
+	if keys := readProjectKeys(t, dataDir); keys["LP-1"] != "proj-1" || keys["LP-2"] != "proj-2" {
+		t.Errorf("linear_projects.json = %v, want LP-1 and LP-2", keys)
+	}
+}
+
+func TestCreateIssueInProjectAndSubIssue(t *testing.T) {
+	client, requests, _ := newTestClient(t, func(w http.ResponseWriter, op string, r trackertest.Request) {
+		switch op {
+		case "projectCreate":
+			trackertest.Reply(w, http.StatusOK, `{"data": {"projectCreate": {"project": {"id": "proj-1", "name": "Tracing"}}}}`)
+		case "issue":
+			trackertest.Reply(w, http.StatusOK, `{"data": {"issue": {"id": "issue-12", "identifier": "ENG-12"}}}`)
+		case "issueCreate":
+			trackertest.Reply(w, http.StatusOK, `{"data": {"issueCreate": {"issue": {"id": "issue-13", "identifier": "ENG-13", "state": {"name": "Todo"}}}}}`)
+		default:
+			t.Errorf("unexpected %s request", op)
+			trackertest.Reply(w, http.StatusOK, `{"data": {}}`)
+		}
+	})
+
+	epic, err := client.CreateTicket(&types.Ticket{Type: types.TicketTypeEpic, Title: "Tracing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := client.CreateTicket(&types.Ticket{Type: types.TicketTypeTask, Title: "Add spans", EpicKey: epic.Key, Priority: "High"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Key != "ENG-13" || task.Status != "Todo" {
+		t.Errorf("created %s with status %q, want ENG-13 in Todo", task.Key, task.Status)
+	}
+	input, _ := variables(t, *requests, "issueCreate")["input"].(map[string]interface{})
+	if input["teamId"] != "team-1" || input["projectId"] != "proj-1" || input["priority"] != float64(2) {
+		t.Errorf("issueCreate input = %v, want team-1, proj-1 and priority 2", input)
+	}
+
+	if _, err := client.CreateTicket(&types.Ticket{Type: types.TicketTypeSubtask, Title: "Sample traces", ParentKey: "eng-12"}); err != nil {
+		t.Fatal(err)
+	}
+	if id := variables(t, *requests, "issue")["id"]; id != "ENG-12" {
+		t.Errorf("looked up parent %v, want ENG-12", id)
+	}
+	input, _ = variables(t, *requests, "issueCreate")["input"].(map[string]interface{})
+	if input["parentId"] != "issue-12" {
+		t.Errorf("sub-issue input = %v, want parentId issue-12", input)
+	}
+}
+
+func TestCreateIssueInUnknownProject(t *testing.T) {
+	client, requests, _ := newTestClient(t, func(w http.ResponseWriter, op string, r trackertest.Request) {
+		t.Errorf("unexpected %s request", op)
+	})
+
+	if _, err := client.CreateTicket(&types.Ticket{Type: types.TicketTypeTask, Title: "Add spans", EpicKey: "LP-7"}); err == nil {
+		t.Error("created an issue in a project with no recorded key")
+	}
+	for _, r := range *requests {
+		if op := operation(r); op != "teams" {
+			t.Errorf("sent %s", op)
+		}
+	}
+}
+
+func TestMatchState(t *testing.T) {
+	states := []workflowState{
+		{ID: "s1", Name: "Backlog", Type: "backlog"},
+		{ID: "s2", Name: "Todo", Type: "unstarted"},
+		{ID: "s3", Name: "In Progress", Type: "started"},
+		{ID: "s4", Name: "In Review", Type: "started"},
+		{ID: "s5", Name: "Shipped", Type: "completed"},
+		{ID: "s6", Name: "Canceled", Type: "canceled"},
+	}
+	tests := []struct {
+		status  string
+		want    string
+		wantErr bool
+	}{
+		{status: "in progress", want: "s3"},
+		{status: "IN_REVIEW", want: "s4"},
+		{status: "todo", want: "s2"},
+		{status: "review", want: "s4"},
+		{status: "Done", want: "s5"},
+		{status: "closed", want: "s5"},
+		{status: "in", wantErr: true}, // In Progress and In Review
+		{status: "Blocked", wantErr: true},
+		{status: " ", wantErr: true},
+	}
+	for _, tt := range tests {
+		state, err := matchState(states, tt.status)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("matchState(%q) = %s, want an error", tt.status, state.Name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("matchState(%q): %v", tt.status, err)
+		} else if state.ID != tt.want {
+			t.Errorf("matchState(%q) = %s, want %s", tt.status, state.ID, tt.want)
+		}
+	}
+}
+
+func TestTransitionIssue(t *testing.T) {
+	client, requests, _ := newTestClient(t, func(w http.ResponseWriter, op string, r trackertest.Request) {
+		switch op {
+		case "issue":
+			trackertest.Reply(w, http.StatusOK, `{"data": {"issue": {"id": "issue-12", "identifier": "ENG-12", "state": {"name": "Todo"}}}}`)
+		case "team":
+			trackertest.Reply(w, http.StatusOK, `{"data": {"team": {"states": {"nodes": [
+				{"id": "s2", "name": "Todo", "type": "unstarted"},
+				{"id": "s3", "name": "In Progress", "type": "started"}
+			]}}}}`)
+		default:
+			trackertest.Reply(w, http.StatusOK, `{"data": {"issueUpdate": {"success": true}}}`)
+		}
+	})
+
+	status, err := client.Transition("ENG-12", "in progress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != "In Progress" {
+		t.Errorf("status = %q, want In Progress", status)
+	}
+	vars := variables(t, *requests, "issueUpdate")
+	if input, _ := vars["input"].(map[string]interface{}); vars["id"] != "issue-12" || input["stateId"] != "s3" {
+		t.Errorf("issueUpdate variables = %v, want issue-12 moved to s3", vars)
+	}
+
+	// An issue already in the state isn't updated again
+	before := len(*requests)
+	if _, err := client.Transition("ENG-12", "Todo"); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range (*requests)[before:] {
+		if operation(r) == "issueUpdate" {
+			t.Error("updated an issue that was already in the state")
+		}
+	}
+}
+
+func TestTransitionProject(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{"Done", "completed"},
+		{"Resolved", "completed"},
+		{"In Progress", "started"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			client, requests, _ := newTestClient(t, func(w http.ResponseWriter, op string, r trackertest.Request) {
+				switch op {
+				case "projectCreate":
+					trackertest.Reply(w, http.StatusOK, `{"data": {"projectCreate": {"project": {"id": "proj-1", "name": "Tracing"}}}}`)
+				default:
+					trackertest.Reply(w, http.StatusOK, `{"data": {"projectUpdate": {"success": true}}}`)
+				}
+			})
+			if _, err := client.CreateTicket(&types.Ticket{Type: types.TicketTypeEpic, Title: "Tracing"}); err != nil {
+				t.Fatal(err)
+			}
+
+			status, err := client.Transition("lp-1", tt.status)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vars := variables(t, *requests, "projectUpdate")
+			if input, _ := vars["input"].(map[string]interface{}); status != tt.want || vars["id"] != "proj-1" || input["state"] != tt.want {
+				t.Errorf("Transition = %q with %v, want proj-1 %s", status, vars, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransitionUnknownProject(t *testing.T) {
+	client, _, _ := newTestClient(t, func(w http.ResponseWriter, op string, r trackertest.Request) {
+		t.Errorf("unexpected %s request", op)
+	})
+	if _, err := client.Transition("LP-4", "Done"); err == nil {
+		t.Error("transitioned a project with no recorded key")
+	}
+}
+
+func TestSearchTicketsGivesProjectsKeysOnce(t *testing.T) {
+	client, requests, dataDir := newTestClient(t, func(w http.ResponseWriter, op string, r trackertest.Request) {
+		_, vars := r.GraphQL()
+		if vars["after"] == nil {
+			trackertest.Reply(w, http.StatusOK, `{"data": {"issues": {
+				"nodes": [
+					{"id": "i1", "identifier": "ENG-1", "title": "Add spans", "priorityLabel": "No priority", "project": {"id": "proj-a"}},
+					{"id": "i2", "identifier": "ENG-2", "title": "Sample traces", "project": {"id": "proj-b"}, "parent": {"identifier": "ENG-1"}}
+				],
+				"pageInfo": {"hasNextPage": true, "endCursor": "c1"}}}}`)
+			return
+		}
+		trackertest.Reply(w, http.StatusOK, `{"data": {"issues": {
+			"nodes": [{"id": "i3", "identifier": "ENG-3", "title": "Trace docs", "priorityLabel": "High", "project": {"id": "proj-a"}}],
+			"pageInfo": {"hasNextPage": false}}}}`)
+	})
+
+	tickets, err := client.SearchTickets("trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*requests) != 2 || len(tickets) != 3 {
+		t.Fatalf("fetched %d pages with %d issues, want 2 pages with 3", len(*requests), len(tickets))
+	}
+	if tickets[0].EpicKey != "LP-1" || tickets[1].EpicKey != "LP-2" || tickets[2].EpicKey != "LP-1" {
+		t.Errorf("epics = %s, %s, %s; want LP-1, LP-2, LP-1", tickets[0].EpicKey, tickets[1].EpicKey, tickets[2].EpicKey)
+	}
+	if tickets[0].Priority != "" || tickets[2].Priority != "High" {
+		t.Errorf("priorities = %q, %q; want none and High", tickets[0].Priority, tickets[2].Priority)
+	}
+	if tickets[1].Type != types.TicketTypeSubtask || tickets[1].ParentKey != "ENG-1" {
+		t.Errorf("ENG-2 = %s with parent %q, want a subtask of ENG-1", tickets[1].Type, tickets[1].ParentKey)
+	}
+	if keys := readProjectKeys(t, dataDir); len(keys) != 2 || keys["LP-1"] != "proj-a" || keys["LP-2"] != "proj-b" {
+		t.Errorf("linear_projects.json = %v, want LP-1 and LP-2", keys)
+	}
+
+	filter, _ := variables(t, *requests, "issues")["filter"].(map[string]interface{})
+	if team, _ := filter["team"].(map[string]interface{}); team == nil || filter["or"] == nil {
+		t.Errorf("search filter = %v, want the team and a title or description match", filter)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	client, _, _ := newTestClient(t, func(w http.ResponseWriter, op string, r trackertest.Request) {
+		trackertest.Reply(w, http.StatusOK, `{"errors": [{"message": "Entity not found", "extensions": {"userPresentableMessage": "Issue ENG-404 doesn't exist"}}]}`)
+	})
+
+	_, err := client.GetTicket("ENG-404")
+	if err == nil || !strings.Contains(err.Error(), "Issue ENG-404 doesn't exist") {
+		t.Errorf("GetTicket error = %v, want the user-presentable message", err)
+	}
+}
@@ -0,0 +1,101 @@
+package linear
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// projectKeys maps the keys jai gives Linear projects, which have no key of their own, to
+// their IDs. It is kept in linear_projects.json in the data directory.
+type projectKeys struct {
+	Next     int               `json:"next"`
+	Projects map[string]string `json:"projects"` // Key -> project ID
+
+	// changed is set when a key is handed out and the map needs saving
+	changed bool
+}
+
+// loadProjectKeys reads the project key map
+func (c *Client) loadProjectKeys() (*projectKeys, error) {
+	keys := &projectKeys{Next: 1, Projects: make(map[string]string)}
+	data, err := os.ReadFile(c.keysPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return keys, nil
+		}
+		return nil, fmt.Errorf("failed to read Linear project keys: %w", err)
+	}
+	if err := json.Unmarshal(data, keys); err != nil {
+		return nil, fmt.Errorf("failed to parse Linear project keys: %w", err)
+	}
+	if keys.Projects == nil {
+		keys.Projects = make(map[string]string)
+	}
+	if keys.Next < 1 {
+		keys.Next = 1
+	}
+	return keys, nil
+}
+
+// saveProjectKeys writes the project key map
+func (c *Client) saveProjectKeys(keys *projectKeys) error {
+	if err := os.MkdirAll(filepath.Dir(c.keysPath), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal Linear project keys: %w", err)
+	}
+	if err := os.WriteFile(c.keysPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write Linear project keys: %w", err)
+	}
+	return nil
+}
+
+// projectID returns the ID of the project with the given key
+func (c *Client) projectID(key string) (string, error) {
+	keys, err := c.loadProjectKeys()
+	if err != nil {
+		return "", err
+	}
+	for k, id := range keys.Projects {
+		if strings.EqualFold(k, key) {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("unknown Linear project %s (not in %s)", key, filepath.Base(c.keysPath))
+}
+
+// projectKey returns the key of a project, handing out the next one and saving the map if
+// the project has none yet
+func (c *Client) projectKey(id string) (string, error) {
+	keys, err := c.loadProjectKeys()
+	if err != nil {
+		return "", err
+	}
+	key := keys.keyFor(id)
+	if keys.changed {
+		if err := c.saveProjectKeys(keys); err != nil {
+			return "", err
+		}
+	}
+	return key, nil
+}
+
+// keyFor returns the key of a project, handing out the next one if the project has none yet
+func (k *projectKeys) keyFor(id string) string {
+	for key, projectID := range k.Projects {
+		if projectID == id {
+			return key
+		}
+	}
+
+	key := fmt.Sprintf("%s-%d", ProjectPrefix, k.Next)
+	k.Projects[key] = id
+	k.Next++
+	k.changed = true
+	return key
+}
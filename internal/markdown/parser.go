@@ -1,19 +1,24 @@
 package markdown
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/lunchboxsushi/jai/internal/convert"
 	"github.com/lunchboxsushi/jai/internal/types"
 )
 
 // Parser handles parsing and writing markdown files
 type Parser struct {
 	dataDir string
+
+	// isCustomField reports whether a metadata name is a mapped custom field
+	isCustomField func(name string) bool
 }
 
 // NewParser creates a new markdown parser
@@ -23,6 +28,13 @@ func NewParser(dataDir string) *Parser {
 	}
 }
 
+// WithCustomFields makes the parser read metadata lines whose names isCustomField accepts,
+// e.g. "StoryPoints: 3", into a ticket's custom fields. Without it, no custom fields are read.
+func (p *Parser) WithCustomFields(isCustomField func(name string) bool) *Parser {
+	p.isCustomField = isCustomField
+	return p
+}
+
 // ParseFile parses a markdown file and extracts tickets
 func (p *Parser) ParseFile(filePath string) (*types.MarkdownFile, error) {
 	data, err := os.ReadFile(filePath)
@@ -52,96 +64,152 @@ func (p *Parser) WriteFile(filePath string, tickets []types.Ticket) error {
 	return os.WriteFile(filePath, []byte(content), 0644)
 }
 
+// ticketSection identifies which part of a ticket block a line belongs to
+type ticketSection int
+
+const (
+	sectionBody ticketSection = iota
+	sectionEnriched
+	sectionMetadata
+	sectionLinks
+	sectionComments
+)
+
+// dueDateFormat is the date layout used for the Due metadata line
+const dueDateFormat = "2006-01-02"
+
+// commentTimeFormat is the timestamp layout used in comment headers
+const commentTimeFormat = "2006-01-02 15:04"
+
+// attachmentRe matches an entry of the Attachments metadata line, e.g. "./img/trace.png (10234)"
+var attachmentRe = regexp.MustCompile(`^(.+) \((\d+)\)$`)
+
+// customFieldRe matches metadata lines for custom fields, e.g. "StoryPoints: 3"
+var customFieldRe = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_]*):\s*(.*)$`)
+
+// linkLineRe matches link lines like "- is blocked by SRE-12 (In Progress)"
+var linkLineRe = regexp.MustCompile(`^- (.+?) ([A-Za-z][A-Za-z0-9_]*-\d+)(?: \((.+)\))?$`)
+
+// commentHeaderRe matches comment headers like "**Jane Doe** (2024-01-02 15:04):"
+var commentHeaderRe = regexp.MustCompile(`^\*\*(.+?)\*\* \((\d{4}-\d{2}-\d{2} \d{2}:\d{2})\):$`)
+
 // extractTickets extracts tickets from markdown content
 func (p *Parser) extractTickets(content, filePath string) []types.Ticket {
 	var tickets []types.Ticket
-	scanner := bufio.NewScanner(strings.NewReader(content))
+	lines := strings.Split(content, "\n")
 
-	lineNum := 0
-	currentTicket := &types.Ticket{}
-	inTicket := false
-	var lines []string
-	inMetadata := false
+	var currentTicket *types.Ticket
+	var bodyLines, enrichedLines, metaLines, linkLines, commentLines []string
+	section := sectionBody
+
+	flush := func() {
+		if currentTicket == nil {
+			return
+		}
+		// Metadata written inline in the body (without a marker) is still honoured,
+		// but the explicit metadata section takes precedence. Custom fields are only read
+		// from the metadata section, so description bullets like "- Goal: ..." stay prose.
+		p.parseMetadataLines(bodyLines, currentTicket, false)
+		p.parseMetadataLines(metaLines, currentTicket, true)
+		currentTicket.Links = parseLinks(linkLines)
+		currentTicket.Comments = p.parseComments(commentLines)
+		currentTicket.RawContent = strings.TrimSpace(strings.Join(bodyLines, "\n"))
+		currentTicket.Enriched = strings.TrimSpace(strings.Join(enrichedLines, "\n"))
+		currentTicket.Description = currentTicket.Enriched
+		if currentTicket.Description == "" {
+			currentTicket.Description = currentTicket.RawContent
+		}
+		tickets = append(tickets, *currentTicket)
+	}
 
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
 
 		// Check for ticket headers
 		if p.isTicketHeader(line) {
 			// Save previous ticket if exists
-			if inTicket {
-				currentTicket.RawContent = strings.TrimSpace(strings.Join(lines, "\n"))
-				tickets = append(tickets, *currentTicket)
-			}
+			flush()
 
 			// Start new ticket
-			currentTicket = p.parseTicketHeader(line, lineNum)
-			inTicket = true
-			inMetadata = false
-			lines = []string{}
+			currentTicket = p.parseTicketHeader(line, i+1)
+			bodyLines, enrichedLines, metaLines, linkLines, commentLines = nil, nil, nil, nil, nil
+			section = sectionBody
 			continue
 		}
 
-		if inTicket {
-			// Check for metadata section start
-			if strings.TrimSpace(line) == "---" {
-				// Look ahead for metadata marker
-				if scanner.Scan() {
-					lineNum++
-					nextLine := strings.TrimSpace(scanner.Text())
-					if nextLine == "*Metadata:*" {
-						inMetadata = true
-						// Parse existing metadata lines before the marker
-						p.parseMetadataLines(lines, currentTicket)
-						lines = []string{}
-						continue
-					} else {
-						// Not metadata, add both lines back
-						lines = append(lines, line)
-						lines = append(lines, scanner.Text())
-					}
-				} else {
-					// End of file, add the line
-					lines = append(lines, line)
-				}
-			} else if inMetadata {
-				// Check for metadata section end
-				if strings.TrimSpace(line) == "---" || strings.TrimSpace(line) == "" {
-					inMetadata = false
-					continue
-				}
-				// Parse metadata line
-				p.parseMetadataLine(line, currentTicket)
-			} else {
-				lines = append(lines, line)
+		if currentTicket == nil {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+
+		// A "---" line followed by a section marker starts a new section
+		if trimmed == "---" && i+1 < len(lines) {
+			switch strings.TrimSpace(lines[i+1]) {
+			case "*Metadata:*":
+				section = sectionMetadata
+				i++
+				continue
+			case "*Enriched:*":
+				section = sectionEnriched
+				i++
+				continue
+			case "*Links:*":
+				section = sectionLinks
+				i++
+				continue
+			case "*Comments:*":
+				section = sectionComments
+				i++
+				continue
 			}
 		}
-	}
 
-	// Don't forget the last ticket
-	if inTicket {
-		// Parse any remaining metadata lines
-		if !inMetadata {
-			p.parseMetadataLines(lines, currentTicket)
+		switch section {
+		case sectionMetadata:
+			// Check for metadata section end
+			if trimmed == "---" || trimmed == "" {
+				section = sectionBody
+				continue
+			}
+			metaLines = append(metaLines, line)
+		case sectionLinks:
+			if trimmed == "---" || trimmed == "" {
+				section = sectionBody
+				continue
+			}
+			linkLines = append(linkLines, line)
+		case sectionComments:
+			// Anything that isn't part of a comment ends the section
+			if !isCommentLine(trimmed) {
+				section = sectionBody
+				bodyLines = append(bodyLines, line)
+				continue
+			}
+			commentLines = append(commentLines, line)
+		case sectionEnriched:
+			enrichedLines = append(enrichedLines, line)
+		default:
+			bodyLines = append(bodyLines, line)
 		}
-		currentTicket.RawContent = strings.TrimSpace(strings.Join(lines, "\n"))
-		tickets = append(tickets, *currentTicket)
 	}
 
+	// Don't forget the last ticket
+	flush()
+
 	return tickets
 }
 
-// parseMetadataLines parses metadata lines for a ticket
-func (p *Parser) parseMetadataLines(lines []string, ticket *types.Ticket) {
+// parseMetadataLines parses metadata lines for a ticket, including custom fields if custom is set
+func (p *Parser) parseMetadataLines(lines []string, ticket *types.Ticket, custom bool) {
 	for _, metaLine := range lines {
 		metaLine = strings.TrimSpace(metaLine)
-		p.parseMetadataLine(metaLine, ticket)
+		p.parseMetadataLine(metaLine, ticket, custom)
 	}
 }
 
 // parseMetadataLine parses a single metadata line
-func (p *Parser) parseMetadataLine(metaLine string, ticket *types.Ticket) {
+func (p *Parser) parseMetadataLine(metaLine string, ticket *types.Ticket, custom bool) {
 	metaLine = strings.TrimSpace(metaLine)
 	if !strings.HasPrefix(metaLine, "- ") {
 		return
@@ -154,10 +222,32 @@ func (p *Parser) parseMetadataLine(metaLine string, ticket *types.Ticket) {
 	switch {
 	case strings.HasPrefix(metaLine, "Key:"):
 		ticket.Key = strings.TrimSpace(strings.TrimPrefix(metaLine, "Key:"))
+	case strings.HasPrefix(metaLine, "Project:"):
+		ticket.Project = strings.TrimSpace(strings.TrimPrefix(metaLine, "Project:"))
+	case strings.HasPrefix(metaLine, "Profile:"):
+		ticket.Profile = strings.TrimSpace(strings.TrimPrefix(metaLine, "Profile:"))
 	case strings.HasPrefix(metaLine, "Status:"):
 		ticket.Status = strings.TrimSpace(strings.TrimPrefix(metaLine, "Status:"))
 	case strings.HasPrefix(metaLine, "Priority:"):
 		ticket.Priority = strings.TrimSpace(strings.TrimPrefix(metaLine, "Priority:"))
+	case strings.HasPrefix(metaLine, "Labels:"):
+		ticket.Labels = convert.SplitList(strings.TrimPrefix(metaLine, "Labels:"))
+	case strings.HasPrefix(metaLine, "Components:"):
+		ticket.Components = convert.SplitList(strings.TrimPrefix(metaLine, "Components:"))
+	case strings.HasPrefix(metaLine, "Assignee:"):
+		ticket.Assignee = strings.TrimSpace(strings.TrimPrefix(metaLine, "Assignee:"))
+	case strings.HasPrefix(metaLine, "Due:"):
+		if due, err := time.ParseInLocation(dueDateFormat, strings.TrimSpace(strings.TrimPrefix(metaLine, "Due:")), time.Local); err == nil {
+			ticket.DueDate = &due
+		}
+	case strings.HasPrefix(metaLine, "Sprint:"):
+		ticket.Sprint = strings.TrimSpace(strings.TrimPrefix(metaLine, "Sprint:"))
+	case strings.HasPrefix(metaLine, "Attachments:"):
+		for _, entry := range convert.SplitList(strings.TrimPrefix(metaLine, "Attachments:")) {
+			if m := attachmentRe.FindStringSubmatch(entry); m != nil {
+				ticket.Attachments = append(ticket.Attachments, types.Attachment{ID: m[2], Path: m[1]})
+			}
+		}
 	case strings.HasPrefix(metaLine, "EpicKey:"):
 		ticket.EpicKey = strings.TrimSpace(strings.TrimPrefix(metaLine, "EpicKey:"))
 	case strings.HasPrefix(metaLine, "ParentKey:"):
@@ -177,7 +267,108 @@ func (p *Parser) parseMetadataLine(metaLine string, ticket *types.Ticket) {
 		ticket.ParentKey = strings.TrimSpace(strings.TrimPrefix(metaLine, "ParentTask:"))
 	case strings.HasPrefix(metaLine, "ParentEpic:"):
 		ticket.EpicKey = strings.TrimSpace(strings.TrimPrefix(metaLine, "ParentEpic:"))
+	default:
+		// Other fields, e.g. "StoryPoints: 3", are custom fields if mapped through jira.custom_fields
+		if !custom || p.isCustomField == nil {
+			return
+		}
+		if m := customFieldRe.FindStringSubmatch(metaLine); m != nil && p.isCustomField(m[1]) {
+			if ticket.CustomFields == nil {
+				ticket.CustomFields = make(map[string]interface{})
+			}
+			ticket.CustomFields[m[1]] = strings.TrimSpace(m[2])
+		}
+	}
+}
+
+// parseLinks parses the lines of a links section
+func parseLinks(lines []string) []types.Link {
+	var links []types.Link
+	for _, line := range lines {
+		if m := linkLineRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			links = append(links, types.Link{Relation: m[1], Key: m[2], Status: m[3]})
+		}
+	}
+	return links
+}
+
+// GenerateLinks generates the links section lines for a ticket
+func (p *Parser) GenerateLinks(ticket types.Ticket) []string {
+	if len(ticket.Links) == 0 {
+		return nil
+	}
+
+	lines := []string{"---", "*Links:*"}
+	for _, link := range ticket.Links {
+		line := fmt.Sprintf("- %s %s", link.Relation, link.Key)
+		if link.Status != "" {
+			line += fmt.Sprintf(" (%s)", link.Status)
+		}
+		lines = append(lines, line)
 	}
+	return lines
+}
+
+// isCommentLine reports whether a line can appear inside a comments section
+func isCommentLine(line string) bool {
+	return line == "" || strings.HasPrefix(line, ">") || commentHeaderRe.MatchString(line)
+}
+
+// parseComments parses the lines of a comments section
+func (p *Parser) parseComments(lines []string) []types.Comment {
+	var comments []types.Comment
+	var current *types.Comment
+	var body []string
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Body = strings.TrimSpace(strings.Join(body, "\n"))
+		comments = append(comments, *current)
+	}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if m := commentHeaderRe.FindStringSubmatch(line); m != nil {
+			flush()
+			created, _ := time.ParseInLocation(commentTimeFormat, m[2], time.Local)
+			current = &types.Comment{Author: m[1], Created: created}
+			body = nil
+			continue
+		}
+		if current != nil && strings.HasPrefix(line, ">") {
+			body = append(body, strings.TrimPrefix(strings.TrimPrefix(line, ">"), " "))
+		}
+	}
+	flush()
+
+	return comments
+}
+
+// GenerateComments generates the comments section lines for a ticket
+func (p *Parser) GenerateComments(ticket types.Ticket) []string {
+	if len(ticket.Comments) == 0 {
+		return nil
+	}
+
+	lines := []string{"---", "*Comments:*"}
+	for i, comment := range ticket.Comments {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, fmt.Sprintf("**%s** (%s):", comment.Author, comment.Created.Local().Format(commentTimeFormat)))
+		for _, bodyLine := range strings.Split(strings.TrimSpace(comment.Body), "\n") {
+			bodyLine = strings.TrimRight(bodyLine, " \t\r")
+			if bodyLine == "" {
+				lines = append(lines, ">")
+			} else {
+				lines = append(lines, "> "+bodyLine)
+			}
+		}
+	}
+
+	return lines
 }
 
 // isTicketHeader checks if a line is a ticket header
@@ -250,38 +441,21 @@ func (p *Parser) GenerateMarkdown(tickets []types.Ticket) string {
 		}
 
 		// Add metadata section
-		metaLines := []string{"---", "*Metadata:*"}
-		if ticket.Key != "" {
-			metaLines = append(metaLines, fmt.Sprintf("- Key: %s", ticket.Key))
-		}
-		if ticket.Status != "" {
-			metaLines = append(metaLines, fmt.Sprintf("- Status: %s", ticket.Status))
-		}
-		if ticket.Priority != "" {
-			metaLines = append(metaLines, fmt.Sprintf("- Priority: %s", ticket.Priority))
-		}
+		metaLines := p.GenerateMetadata(ticket)
+		metaLines = append(metaLines, "")
+		lines = append(lines, metaLines...)
 
-		// Add appropriate parent references based on ticket type
-		switch ticket.Type {
-		case types.TicketTypeEpic:
-			// Epics don't have parents, but may have EpicKey for consistency
-			if ticket.EpicKey != "" {
-				metaLines = append(metaLines, fmt.Sprintf("- EpicKey: %s", ticket.EpicKey))
-			}
-		case types.TicketTypeTask:
-			// Tasks have ParentKey (epic)
-			if ticket.EpicKey != "" {
-				metaLines = append(metaLines, fmt.Sprintf("- ParentKey: %s", ticket.EpicKey))
-			}
-		case types.TicketTypeSubtask:
-			// Subtasks have TaskKey (parent task)
-			if ticket.ParentKey != "" {
-				metaLines = append(metaLines, fmt.Sprintf("- TaskKey: %s", ticket.ParentKey))
-			}
+		// Add links section
+		if linkLines := p.GenerateLinks(ticket); len(linkLines) > 0 {
+			lines = append(lines, linkLines...)
+			lines = append(lines, "")
 		}
 
-		metaLines = append(metaLines, "")
-		lines = append(lines, metaLines...)
+		// Add comments section
+		if commentLines := p.GenerateComments(ticket); len(commentLines) > 0 {
+			lines = append(lines, commentLines...)
+			lines = append(lines, "")
+		}
 
 		lines = append(lines, "")
 		lines = append(lines, "")
@@ -290,6 +464,274 @@ func (p *Parser) GenerateMarkdown(tickets []types.Ticket) string {
 	return strings.Join(lines, "\n")
 }
 
+// GenerateMetadata generates the metadata section lines for a ticket
+func (p *Parser) GenerateMetadata(ticket types.Ticket) []string {
+	metaLines := []string{"---", "*Metadata:*"}
+	if ticket.Key != "" {
+		metaLines = append(metaLines, fmt.Sprintf("- Key: %s", ticket.Key))
+	}
+	if ticket.Project != "" {
+		metaLines = append(metaLines, fmt.Sprintf("- Project: %s", ticket.Project))
+	}
+	if ticket.Profile != "" {
+		metaLines = append(metaLines, fmt.Sprintf("- Profile: %s", ticket.Profile))
+	}
+	if ticket.Status != "" {
+		metaLines = append(metaLines, fmt.Sprintf("- Status: %s", ticket.Status))
+	}
+	if ticket.Priority != "" {
+		metaLines = append(metaLines, fmt.Sprintf("- Priority: %s", ticket.Priority))
+	}
+	if len(ticket.Labels) > 0 {
+		metaLines = append(metaLines, fmt.Sprintf("- Labels: %s", strings.Join(ticket.Labels, ", ")))
+	}
+	if len(ticket.Components) > 0 {
+		metaLines = append(metaLines, fmt.Sprintf("- Components: %s", strings.Join(ticket.Components, ", ")))
+	}
+	if ticket.Assignee != "" {
+		metaLines = append(metaLines, fmt.Sprintf("- Assignee: %s", ticket.Assignee))
+	}
+	if ticket.DueDate != nil {
+		metaLines = append(metaLines, fmt.Sprintf("- Due: %s", ticket.DueDate.Format(dueDateFormat)))
+	}
+	if ticket.Sprint != "" {
+		metaLines = append(metaLines, fmt.Sprintf("- Sprint: %s", ticket.Sprint))
+	}
+	if len(ticket.Attachments) > 0 {
+		var entries []string
+		for _, attachment := range ticket.Attachments {
+			entries = append(entries, fmt.Sprintf("%s (%s)", attachment.Path, attachment.ID))
+		}
+		metaLines = append(metaLines, fmt.Sprintf("- Attachments: %s", strings.Join(entries, ", ")))
+	}
+
+	// Custom fields are written in a stable order so files don't churn between syncs
+	names := make([]string, 0, len(ticket.CustomFields))
+	for name := range ticket.CustomFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		metaLines = append(metaLines, strings.TrimSpace(fmt.Sprintf("- %s: %v", name, ticket.CustomFields[name])))
+	}
+
+	// Add appropriate parent references based on ticket type
+	switch ticket.Type {
+	case types.TicketTypeEpic:
+		// Epics don't have parents, but may have EpicKey for consistency
+		if ticket.EpicKey != "" {
+			metaLines = append(metaLines, fmt.Sprintf("- EpicKey: %s", ticket.EpicKey))
+		}
+	case types.TicketTypeTask:
+		// Tasks have ParentKey (epic)
+		if ticket.EpicKey != "" {
+			metaLines = append(metaLines, fmt.Sprintf("- ParentKey: %s", ticket.EpicKey))
+		}
+	case types.TicketTypeSubtask:
+		// Subtasks have TaskKey (parent task) and optionally the epic
+		if ticket.ParentKey != "" {
+			metaLines = append(metaLines, fmt.Sprintf("- TaskKey: %s", ticket.ParentKey))
+		}
+		if ticket.EpicKey != "" {
+			metaLines = append(metaLines, fmt.Sprintf("- EpicKey: %s", ticket.EpicKey))
+		}
+	}
+
+	return metaLines
+}
+
+// UpdateTicket rewrites the metadata, links and comments sections of the ticket with the given
+// key in place. The header, body and enriched content of the ticket, as well as any other
+// tickets in the file, are left untouched.
+func (p *Parser) UpdateTicket(filePath string, ticket types.Ticket) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	sections := []struct {
+		marker string
+		lines  []string
+	}{
+		{"*Metadata:*", p.GenerateMetadata(ticket)},
+		{"*Links:*", p.GenerateLinks(ticket)},
+		{"*Comments:*", p.GenerateComments(ticket)},
+	}
+
+	for _, section := range sections {
+		start, end, err := p.ticketBlock(lines, filePath, ticket.Key)
+		if err != nil {
+			return err
+		}
+		lines = replaceSection(lines, start, end, section.marker, section.lines)
+	}
+
+	return os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// UpdateTitle replaces the title in the header of the ticket with the given key, keeping
+// its type and key. The rest of the file is left untouched.
+func (p *Parser) UpdateTitle(filePath, key, title string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	start, _, err := p.ticketBlock(lines, filePath, key)
+	if err != nil {
+		return err
+	}
+
+	ticket := p.parseTicketHeader(lines[start], start+1)
+	ticket.Title = title
+	ticket.Key = key
+	lines[start] = p.generateHeader(*ticket)
+
+	return os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// UpdateDescription replaces the description of the ticket with the given key in place.
+// The enriched section holds the description when there is one, otherwise the body
+// under the header does. Metadata, comments and other tickets are left untouched.
+func (p *Parser) UpdateDescription(filePath, key, description string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	start, end, err := p.ticketBlock(lines, filePath, key)
+	if err != nil {
+		return err
+	}
+
+	// The description runs up to the next section (or the enriched section's end)
+	from, to := start+1, end
+	for i := start + 1; i < end-1; i++ {
+		if strings.TrimSpace(lines[i]) == "---" && strings.TrimSpace(lines[i+1]) == "*Enriched:*" {
+			from = i + 2
+			break
+		}
+	}
+	for i := from; i < end-1; i++ {
+		if strings.TrimSpace(lines[i]) == "---" && isSectionMarker(strings.TrimSpace(lines[i+1])) {
+			to = i
+			break
+		}
+	}
+
+	content := []string{""}
+	if from > start+1 {
+		// Enriched content follows its marker directly
+		content = nil
+	}
+	content = append(content, strings.Split(strings.TrimSpace(description), "\n")...)
+	content = append(content, "")
+
+	updated := make([]string, 0, len(lines)+len(content))
+	updated = append(updated, lines[:from]...)
+	updated = append(updated, content...)
+	updated = append(updated, lines[to:]...)
+
+	return os.WriteFile(filePath, []byte(strings.Join(updated, "\n")), 0644)
+}
+
+// isSectionMarker reports whether line introduces one of the sections following "---"
+func isSectionMarker(line string) bool {
+	switch line {
+	case "*Enriched:*", "*Metadata:*", "*Links:*", "*Comments:*":
+		return true
+	}
+	return false
+}
+
+// ticketBlock returns the range of lines belonging to the ticket with the given key
+func (p *Parser) ticketBlock(lines []string, filePath, key string) (int, int, error) {
+	start, end := -1, len(lines)
+	for _, t := range p.extractTickets(strings.Join(lines, "\n"), filePath) {
+		if start >= 0 {
+			end = t.LineNumber - 1
+			break
+		}
+		if t.Key == key {
+			start = t.LineNumber - 1
+		}
+	}
+	if start < 0 {
+		return 0, 0, fmt.Errorf("ticket %s not found in %s", key, filePath)
+	}
+	return start, end, nil
+}
+
+// replaceSection swaps the "---"/marker section inside lines[start:end] for section.
+// A missing section is appended after the last non-empty line of the block, and an
+// empty replacement removes the section altogether.
+func replaceSection(lines []string, start, end int, marker string, section []string) []string {
+	// Find the existing section, if any
+	sectionStart, sectionEnd := -1, -1
+	for i := start + 1; i < end-1; i++ {
+		if strings.TrimSpace(lines[i]) == "---" && strings.TrimSpace(lines[i+1]) == marker {
+			sectionStart = i
+			sectionEnd = sectionLength(lines, i+2, end, marker)
+			break
+		}
+	}
+
+	if sectionStart < 0 && len(section) == 0 {
+		return lines
+	}
+
+	// Without an existing section, append one after the last non-empty line of the block
+	inserting := sectionStart < 0
+	if inserting {
+		sectionStart = end
+		for sectionStart > start+1 && strings.TrimSpace(lines[sectionStart-1]) == "" {
+			sectionStart--
+		}
+		sectionEnd = sectionStart
+	}
+
+	updated := make([]string, 0, len(lines)+len(section)+2)
+	updated = append(updated, lines[:sectionStart]...)
+	if inserting {
+		// Keep "---" from turning the preceding paragraph into a heading
+		updated = append(updated, "")
+	}
+	updated = append(updated, section...)
+	if inserting && (sectionEnd >= len(lines) || strings.TrimSpace(lines[sectionEnd]) != "") {
+		updated = append(updated, "")
+	}
+	updated = append(updated, lines[sectionEnd:]...)
+
+	return updated
+}
+
+// sectionLength returns the index just past the content of the section starting at i
+func sectionLength(lines []string, i, end int, marker string) int {
+	switch marker {
+	case "*Comments:*":
+		// Comments span blank lines, so stop after the last quoted or header line
+		last := i
+		for ; i < end && isCommentLine(strings.TrimSpace(lines[i])); i++ {
+			if strings.TrimSpace(lines[i]) != "" {
+				last = i + 1
+			}
+		}
+		return last
+	default:
+		for i < end && strings.HasPrefix(strings.TrimSpace(lines[i]), "- ") {
+			i++
+		}
+		// Swallow an explicit closing separator, unless it opens the next section
+		if i < end && strings.TrimSpace(lines[i]) == "---" && (i+1 >= end || !strings.HasPrefix(strings.TrimSpace(lines[i+1]), "*")) {
+			i++
+		}
+		return i
+	}
+}
+
 // generateHeader generates a markdown header for a ticket
 func (p *Parser) generateHeader(ticket types.Ticket) string {
 	prefix := ""
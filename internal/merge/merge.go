@@ -0,0 +1,171 @@
+package merge
+
+import (
+	"strings"
+)
+
+// Conflict markers written around overlapping changes, in the style of git
+const (
+	ConflictStart     = "<<<<<<< local"
+	ConflictSeparator = "======="
+	ConflictEnd       = ">>>>>>> remote"
+)
+
+// Result is the outcome of a three-way merge
+type Result struct {
+	Lines     []string
+	Conflicts int // Number of conflicting hunks, each wrapped in conflict markers
+}
+
+// Merge combines the changes made to base by local and remote. Changes that don't
+// overlap are applied together; overlapping ones are kept side by side between
+// conflict markers, local first.
+func Merge(base, local, remote []string) Result {
+	localMatch := matches(base, local)
+	remoteMatch := matches(base, remote)
+
+	var result Result
+	i, l, r := 0, 0, 0
+	for {
+		// The next base line both sides kept unchanged anchors the hunk before it
+		k := i
+		for k < len(base) && (localMatch[k] < 0 || remoteMatch[k] < 0) {
+			k++
+		}
+		lEnd, rEnd := len(local), len(remote)
+		if k < len(base) {
+			lEnd, rEnd = localMatch[k], remoteMatch[k]
+		}
+
+		result.resolve(base[i:k], local[l:lEnd], remote[r:rEnd])
+
+		if k >= len(base) {
+			break
+		}
+		result.Lines = append(result.Lines, base[k])
+		i, l, r = k+1, lEnd+1, rEnd+1
+	}
+
+	return result
+}
+
+// resolve appends the merge of one hunk where base, local and remote may differ
+func (res *Result) resolve(base, local, remote []string) {
+	switch {
+	case equal(local, remote), equal(remote, base):
+		res.Lines = append(res.Lines, local...)
+	case equal(local, base):
+		res.Lines = append(res.Lines, remote...)
+	default:
+		res.Conflicts++
+		res.Lines = append(res.Lines, ConflictStart)
+		res.Lines = append(res.Lines, local...)
+		res.Lines = append(res.Lines, ConflictSeparator)
+		res.Lines = append(res.Lines, remote...)
+		res.Lines = append(res.Lines, ConflictEnd)
+	}
+}
+
+// HasConflicts reports whether text still contains unresolved conflict markers
+func HasConflicts(text string) bool {
+	var started bool
+	for _, line := range strings.Split(text, "\n") {
+		switch strings.TrimSpace(line) {
+		case ConflictStart:
+			started = true
+		case ConflictEnd:
+			if started {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Diff returns a minimal line diff between a and b using the longest common subsequence.
+// Removed lines are prefixed with "- " and added lines with "+ ".
+func Diff(a, b []string) []string {
+	lcs := lcsTable(a, b)
+
+	var out []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "- "+a[i])
+			i++
+		default:
+			out = append(out, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		out = append(out, "+ "+b[j])
+	}
+	return out
+}
+
+// matches returns, for every line of a, the index of the line of b it is paired
+// with in their longest common subsequence, or -1 if it was changed or removed
+func matches(a, b []string) []int {
+	lcs := lcsTable(a, b)
+
+	paired := make([]int, len(a))
+	for i := range paired {
+		paired[i] = -1
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			paired[i] = j
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return paired
+}
+
+// lcsTable returns the suffix table where lcs[i][j] is the length of the longest
+// common subsequence of a[i:] and b[j:]
+func lcsTable(a, b []string) [][]int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	return lcs
+}
+
+// equal reports whether two slices hold the same lines
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package merge
+
+import (
+	"strings"
+	"testing"
+)
+
+// lines splits a test document into lines, with "" being no lines at all
+func lines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+func TestMerge(t *testing.T) {
+	conflict := func(local, remote string) string {
+		return strings.Join([]string{ConflictStart, local, ConflictSeparator, remote, ConflictEnd}, "\n")
+	}
+
+	tests := []struct {
+		name                string
+		base, local, remote string
+		want                string
+		conflicts           int
+	}{
+		{
+			name:   "local edit only",
+			base:   "a\nb\nc",
+			local:  "a\nB\nc",
+			remote: "a\nb\nc",
+			want:   "a\nB\nc",
+		},
+		{
+			name:   "remote edit only",
+			base:   "a\nb\nc",
+			local:  "a\nb\nc",
+			remote: "a\nb\nc\nd",
+			want:   "a\nb\nc\nd",
+		},
+		{
+			name:   "edits on both sides that don't overlap",
+			base:   "a\nb\nc\nd\ne",
+			local:  "A\nb\nc\nd\ne",
+			remote: "a\nb\nc\nd\nE",
+			want:   "A\nb\nc\nd\nE",
+		},
+		{
+			name:   "remote removes a line local didn't touch",
+			base:   "a\nb\nc\nd",
+			local:  "a\nb\nc\nD",
+			remote: "a\nc\nd",
+			want:   "a\nc\nD",
+		},
+		{
+			name:   "identical edits on both sides",
+			base:   "a\nb\nc",
+			local:  "a\nX\nc",
+			remote: "a\nX\nc",
+			want:   "a\nX\nc",
+		},
+		{
+			name:      "overlapping edits",
+			base:      "a\nb\nc",
+			local:     "a\nL\nc",
+			remote:    "a\nR\nc",
+			want:      "a\n" + conflict("L", "R") + "\nc",
+			conflicts: 1,
+		},
+		{
+			name:      "two overlapping hunks",
+			base:      "a\nb\nc\nd\ne",
+			local:     "a\nL1\nc\nL2\ne",
+			remote:    "a\nR1\nc\nR2\ne",
+			want:      "a\n" + conflict("L1", "R1") + "\nc\n" + conflict("L2", "R2") + "\ne",
+			conflicts: 2,
+		},
+		{
+			name: "everything empty",
+		},
+		{
+			name:  "empty base and remote",
+			local: "new",
+			want:  "new",
+		},
+		{
+			name:   "empty base and local",
+			remote: "new",
+			want:   "new",
+		},
+		{
+			name:      "empty base with different text on both sides",
+			local:     "mine",
+			remote:    "theirs",
+			want:      conflict("mine", "theirs"),
+			conflicts: 1,
+		},
+		{
+			name:   "local empties the text",
+			base:   "a\nb",
+			remote: "a\nb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Merge(lines(tt.base), lines(tt.local), lines(tt.remote))
+			got := strings.Join(result.Lines, "\n")
+			if got != tt.want {
+				t.Errorf("Merge lines:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if result.Conflicts != tt.conflicts {
+				t.Errorf("Conflicts = %d, want %d", result.Conflicts, tt.conflicts)
+			}
+			if HasConflicts(got) != (tt.conflicts > 0) {
+				t.Errorf("HasConflicts = %v with %d conflicts", HasConflicts(got), tt.conflicts)
+			}
+		})
+	}
+}
+
+func TestHasConflicts(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"a\nb", false},
+		{ConflictStart + "\nL\n" + ConflictSeparator + "\nR\n" + ConflictEnd, true},
+		{"  " + ConflictStart + "\nL\n" + ConflictEnd + "  ", true},
+		{ConflictStart + "\nL\n" + ConflictSeparator + "\nR", false},
+		{ConflictEnd + "\n" + ConflictStart, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := HasConflicts(tt.text); got != tt.want {
+			t.Errorf("HasConflicts(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
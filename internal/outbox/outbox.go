@@ -0,0 +1,198 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// PlaceholderProject is the project part of the keys given to tickets that are waiting
+// to be created in Jira, e.g. PENDING-3
+const PlaceholderProject = "PENDING"
+
+var placeholderRe = regexp.MustCompile(`^` + PlaceholderProject + `-\d+$`)
+
+// IsPlaceholder reports whether a key was handed out by the outbox rather than by Jira
+func IsPlaceholder(key string) bool {
+	return placeholderRe.MatchString(key)
+}
+
+// Kind is what a queued operation does in Jira
+type Kind string
+
+const (
+	KindCreate Kind = "create"
+	KindUpdate Kind = "update"
+)
+
+// Operation is a Jira write that failed and is waiting to be replayed
+type Operation struct {
+	ID       string           `json:"id"`
+	Kind     Kind             `json:"kind"`
+	Key      string           `json:"key"`            // Placeholder key for creates, Jira key for updates
+	Type     types.TicketType `json:"type,omitempty"` // Orders creates so parents go first
+	Queued   time.Time        `json:"queued"`
+	Attempts int              `json:"attempts,omitempty"`
+	Error    string           `json:"error,omitempty"` // Last error seen for this operation
+
+	// Created is the key Jira gave a queued create. It is saved as soon as the create goes
+	// through, so a push that fails while updating local files resumes instead of creating
+	// the ticket again.
+	Created string `json:"created,omitempty"`
+}
+
+// state is the on-disk layout of the outbox
+type state struct {
+	NextPlaceholder int          `json:"next_placeholder"`
+	Operations      []*Operation `json:"operations"`
+}
+
+// Outbox keeps failed Jira creates and updates on disk so they can be pushed later
+type Outbox struct {
+	outboxPath string
+	state      state
+}
+
+// New creates a new outbox in the data directory
+func New(dataDir string) *Outbox {
+	return &Outbox{
+		outboxPath: filepath.Join(dataDir, "outbox.json"),
+	}
+}
+
+// Load loads the outbox from disk
+func (o *Outbox) Load() error {
+	data, err := os.ReadFile(o.outboxPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Nothing queued yet
+			o.state = state{}
+			return nil
+		}
+		return fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &o.state); err != nil {
+		return fmt.Errorf("failed to parse outbox: %w", err)
+	}
+
+	return nil
+}
+
+// Save saves the outbox to disk
+func (o *Outbox) Save() error {
+	// Ensure directory exists
+	dir := filepath.Dir(o.outboxPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(o.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox: %w", err)
+	}
+
+	if err := os.WriteFile(o.outboxPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write outbox: %w", err)
+	}
+
+	return nil
+}
+
+// NextPlaceholder hands out a new placeholder key for a ticket that couldn't be created
+func (o *Outbox) NextPlaceholder() string {
+	o.state.NextPlaceholder++
+	return fmt.Sprintf("%s-%d", PlaceholderProject, o.state.NextPlaceholder)
+}
+
+// Add queues an operation and returns it. An operation already queued for the same kind
+// and key is reused, so retrying a failed update doesn't push it twice; its queued time
+// moves to now, as it now carries the latest local edit. Updates to a ticket that is
+// still waiting to be created are dropped, since the create sends the whole ticket.
+func (o *Outbox) Add(kind Kind, key string, ticketType types.TicketType, cause string) *Operation {
+	if kind == KindUpdate && o.Find(KindCreate, key) != nil {
+		return o.Find(KindCreate, key)
+	}
+	if op := o.Find(kind, key); op != nil {
+		op.Error = cause
+		op.Queued = time.Now()
+		return op
+	}
+
+	op := &Operation{
+		ID:     fmt.Sprintf("%d", time.Now().UnixNano()),
+		Kind:   kind,
+		Key:    key,
+		Type:   ticketType,
+		Queued: time.Now(),
+		Error:  cause,
+	}
+	o.state.Operations = append(o.state.Operations, op)
+	return op
+}
+
+// Find returns the queued operation of a kind for a key, or nil
+func (o *Outbox) Find(kind Kind, key string) *Operation {
+	for _, op := range o.state.Operations {
+		if op.Kind == kind && op.Key == key {
+			return op
+		}
+	}
+	return nil
+}
+
+// Remove drops an operation from the outbox
+func (o *Outbox) Remove(op *Operation) {
+	for i, queued := range o.state.Operations {
+		if queued == op {
+			o.state.Operations = append(o.state.Operations[:i], o.state.Operations[i+1:]...)
+			return
+		}
+	}
+}
+
+// RenameKey points queued operations at a ticket's new key once it exists in Jira
+func (o *Outbox) RenameKey(oldKey, newKey string) {
+	for _, op := range o.state.Operations {
+		if op.Key == oldKey {
+			op.Key = newKey
+		}
+	}
+}
+
+// Len returns the number of queued operations
+func (o *Outbox) Len() int {
+	return len(o.state.Operations)
+}
+
+// Pending returns the queued operations in the order they should be replayed: creates
+// first, epics before tasks before subtasks so parents exist before their children, then
+// updates. Operations of the same rank keep the order they were queued in.
+func (o *Outbox) Pending() []*Operation {
+	pending := append([]*Operation(nil), o.state.Operations...)
+	sort.SliceStable(pending, func(i, j int) bool {
+		return rank(pending[i]) < rank(pending[j])
+	})
+	return pending
+}
+
+// rank orders operations for replay
+func rank(op *Operation) int {
+	if op.Kind != KindCreate {
+		return 3
+	}
+	switch op.Type {
+	case types.TicketTypeEpic:
+		return 0
+	case types.TicketTypeTask:
+		return 1
+	default:
+		return 2
+	}
+}
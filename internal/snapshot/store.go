@@ -0,0 +1,88 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Snapshot is a ticket's content as of the last sync where local and Jira agreed,
+// used as the common base for three-way merges
+type Snapshot struct {
+	Key         string    `json:"key"`
+	Hash        string    `json:"hash"`
+	Description string    `json:"description"`
+	SyncedAt    time.Time `json:"synced_at"`
+}
+
+// Store keeps one snapshot file per ticket in the data directory
+type Store struct {
+	dir string
+}
+
+// NewStore creates a new snapshot store
+func NewStore(dataDir string) *Store {
+	return &Store{
+		dir: filepath.Join(dataDir, "snapshots"),
+	}
+}
+
+// Get returns the snapshot for a ticket, or nil if it has never been synced
+func (s *Store) Get(key string) (*Snapshot, error) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshot for %s: %w", key, err)
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot for %s: %w", key, err)
+	}
+
+	// A snapshot edited or truncated by hand can't be trusted as a merge base
+	if snap.Hash != Hash(snap.Description) {
+		return nil, nil
+	}
+	return &snap, nil
+}
+
+// Put records the synced content of a ticket
+func (s *Store) Put(key, description string) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	snap := Snapshot{
+		Key:         key,
+		Hash:        Hash(description),
+		Description: description,
+		SyncedAt:    time.Now(),
+	}
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	if err := os.WriteFile(s.path(key), data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot for %s: %w", key, err)
+	}
+	return nil
+}
+
+// Hash returns the content hash stored with a snapshot
+func Hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// path returns the snapshot file for a ticket
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
@@ -0,0 +1,165 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIError is an error response from a tracker's REST or GraphQL API
+type APIError struct {
+	Tracker    string // Tracker name, e.g. "GitHub"
+	StatusCode int
+	Message    string
+	Errors     []string // Per-field or GraphQL errors
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	details := e.Message
+	if len(e.Errors) > 0 {
+		details = strings.TrimPrefix(strings.TrimSpace(details+": "+strings.Join(e.Errors, "; ")), ": ")
+	}
+	if e.StatusCode == http.StatusOK {
+		// GraphQL reports errors in a successful response
+		return fmt.Sprintf("%s returned errors: %s", e.Tracker, details)
+	}
+	if details == "" {
+		return fmt.Sprintf("%s returned %d %s", e.Tracker, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("%s returned %d %s: %s", e.Tracker, e.StatusCode, http.StatusText(e.StatusCode), details)
+}
+
+// APIClient sends JSON requests to a tracker's HTTP API
+type APIClient struct {
+	tracker string
+	baseURL string
+	header  http.Header
+	details func(body []byte) (string, []string)
+	http    *http.Client
+}
+
+// NewAPIClient creates a client for the API at baseURL. The header is sent with every
+// request, e.g. for authentication, and details reads the message and per-field errors
+// from a JSON error response; bodies it can't read are used as the message.
+func NewAPIClient(tracker, baseURL string, header http.Header, details func(body []byte) (string, []string)) *APIClient {
+	return &APIClient{
+		tracker: tracker,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		header:  header,
+		details: details,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Do sends a JSON request to a path below the base URL and decodes the response into out,
+// if given
+func (c *APIClient) Do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return c.newAPIError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", c.tracker, err)
+	}
+	return nil
+}
+
+// GraphQL sends a GraphQL request to the base URL and decodes its data into out, if given.
+// Errors listed in the response are returned as an APIError.
+func (c *APIClient) GraphQL(query string, variables map[string]interface{}, out interface{}) error {
+	var body struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err := c.Do(http.MethodPost, "", map[string]interface{}{"query": query, "variables": variables}, &body); err != nil {
+		return err
+	}
+	if len(body.Errors) > 0 {
+		return &APIError{Tracker: c.tracker, StatusCode: http.StatusOK, Errors: graphQLMessages(body.Errors)}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body.Data, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", c.tracker, err)
+	}
+	return nil
+}
+
+// newAPIError reads an error response into an APIError
+func (c *APIClient) newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{Tracker: c.tracker, StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if c.details == nil || !json.Valid(data) {
+		apiErr.Message = strings.TrimSpace(string(data))
+		return apiErr
+	}
+	apiErr.Message, apiErr.Errors = c.details(data)
+	return apiErr
+}
+
+// graphQLError is an entry of a GraphQL response's errors list
+type graphQLError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		UserPresentableMessage string `json:"userPresentableMessage"`
+	} `json:"extensions"`
+}
+
+// graphQLMessages returns the messages of GraphQL errors, preferring the ones meant for users
+func graphQLMessages(errs []graphQLError) []string {
+	var messages []string
+	for _, e := range errs {
+		if e.Extensions.UserPresentableMessage != "" {
+			messages = append(messages, e.Extensions.UserPresentableMessage)
+		} else {
+			messages = append(messages, e.Message)
+		}
+	}
+	return messages
+}
+
+// GraphQLErrorDetails reads the errors list of a GraphQL error response, for NewAPIClient
+func GraphQLErrorDetails(body []byte) (string, []string) {
+	var parsed struct {
+		Errors []graphQLError `json:"errors"`
+	}
+	if json.Unmarshal(body, &parsed) != nil || len(parsed.Errors) == 0 {
+		return strings.TrimSpace(string(body)), nil
+	}
+	return "", graphQLMessages(parsed.Errors)
+}
@@ -0,0 +1,250 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jai/internal/markdown"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// DefaultLocalPrefix is the project part of keys handed out by the local backend, e.g. LOCAL-42
+const DefaultLocalPrefix = "LOCAL"
+
+var _ Backend = (*Local)(nil)
+
+// defaultLocalStatus is the status new local tickets start in
+const defaultLocalStatus = "To Do"
+
+// Local is a Backend for working without a shared tracker. The markdown files in the
+// tickets directory are the tracker: it only hands out sequential keys, and reads and
+// updates tickets in place.
+type Local struct {
+	dataDir    string
+	prefix     string
+	parser     *markdown.Parser
+	keysPath   string
+	ticketsDir string
+}
+
+// localKeys is the on-disk layout of the key counter
+type localKeys struct {
+	Next int `json:"next"`
+}
+
+// NewLocal creates a local backend in the data directory that hands out keys with the
+// given prefix
+func NewLocal(dataDir, prefix string) *Local {
+	if prefix == "" {
+		prefix = DefaultLocalPrefix
+	}
+	return &Local{
+		dataDir:    dataDir,
+		prefix:     strings.ToUpper(prefix),
+		parser:     markdown.NewParser(dataDir),
+		keysPath:   filepath.Join(dataDir, "local_keys.json"),
+		ticketsDir: filepath.Join(dataDir, "tickets"),
+	}
+}
+
+// CreateTicket assigns the next key to a ticket. The caller writes it to its markdown file.
+func (l *Local) CreateTicket(ticket *types.Ticket) (*types.Ticket, error) {
+	next, err := l.nextNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	created := *ticket
+	created.Key = fmt.Sprintf("%s-%d", l.prefix, next)
+	created.ID = strconv.Itoa(next)
+	if created.Status == "" {
+		created.Status = defaultLocalStatus
+	}
+	created.Created = time.Now()
+	created.Updated = time.Now()
+
+	if err := l.saveKeys(localKeys{Next: next + 1}); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetTicket reads a ticket from the tickets directory
+func (l *Local) GetTicket(key string) (*types.Ticket, error) {
+	_, ticket, err := l.find(key)
+	if err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// UpdateTicket writes a ticket's metadata back to its markdown file
+func (l *Local) UpdateTicket(ticket *types.Ticket) error {
+	path, _, err := l.find(ticket.Key)
+	if err != nil {
+		return err
+	}
+	if err := l.parser.UpdateTicket(path, *ticket); err != nil {
+		return fmt.Errorf("failed to update %s: %w", ticket.Key, err)
+	}
+	return nil
+}
+
+// SearchTickets returns the tickets whose key, title, type, status or labels contain every
+// word of the query, case-insensitively. An empty query returns every ticket.
+func (l *Local) SearchTickets(query string) ([]*types.Ticket, error) {
+	mdFiles, err := l.ticketFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := strings.Fields(strings.ToLower(query))
+	var tickets []*types.Ticket
+	for _, mdFile := range mdFiles {
+		for i := range mdFile.Tickets {
+			ticket := &mdFile.Tickets[i]
+			if ticket.Key == "" {
+				continue
+			}
+			text := strings.ToLower(strings.Join(append([]string{
+				ticket.Key, ticket.Title, string(ticket.Type), ticket.Status,
+			}, ticket.Labels...), " "))
+			matched := true
+			for _, term := range terms {
+				if !strings.Contains(text, term) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				tickets = append(tickets, ticket)
+			}
+		}
+	}
+	return tickets, nil
+}
+
+// Transition sets a ticket's status. Local tickets have no workflow, so any status is allowed.
+func (l *Local) Transition(key, status string) (string, error) {
+	status = strings.TrimSpace(status)
+	if status == "" {
+		return "", fmt.Errorf("no status given for %s", key)
+	}
+
+	path, ticket, err := l.find(key)
+	if err != nil {
+		return "", err
+	}
+	ticket.Status = status
+	ticket.Updated = time.Now()
+	if err := l.parser.UpdateTicket(path, *ticket); err != nil {
+		return "", fmt.Errorf("failed to transition %s: %w", key, err)
+	}
+	return status, nil
+}
+
+// nextNumber returns the number for the next key. Keys already in the tickets directory are
+// never handed out again, even if the counter file was lost.
+func (l *Local) nextNumber() (int, error) {
+	keys, err := l.loadKeys()
+	if err != nil {
+		return 0, err
+	}
+	next := keys.Next
+	if next < 1 {
+		next = 1
+	}
+
+	mdFiles, err := l.ticketFiles()
+	if err != nil {
+		return 0, err
+	}
+	for _, mdFile := range mdFiles {
+		for _, ticket := range mdFile.Tickets {
+			project, number, ok := strings.Cut(ticket.Key, "-")
+			if !ok || project != l.prefix {
+				continue
+			}
+			if n, err := strconv.Atoi(number); err == nil && n >= next {
+				next = n + 1
+			}
+		}
+	}
+	return next, nil
+}
+
+// find returns the file and ticket for a key
+func (l *Local) find(key string) (string, *types.Ticket, error) {
+	mdFiles, err := l.ticketFiles()
+	if err != nil {
+		return "", nil, err
+	}
+	for _, mdFile := range mdFiles {
+		for _, ticket := range mdFile.Tickets {
+			if strings.EqualFold(ticket.Key, key) {
+				return mdFile.Path, &ticket, nil
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("no local ticket found with key %s", key)
+}
+
+// ticketFiles parses every markdown file in the tickets directory
+func (l *Local) ticketFiles() ([]*types.MarkdownFile, error) {
+	files, err := os.ReadDir(l.ticketsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not read tickets directory: %w", err)
+	}
+
+	var mdFiles []*types.MarkdownFile
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".md") {
+			continue
+		}
+		mdFile, err := l.parser.ParseFile(filepath.Join(l.ticketsDir, file.Name()))
+		if err != nil {
+			continue
+		}
+		mdFiles = append(mdFiles, mdFile)
+	}
+	return mdFiles, nil
+}
+
+// loadKeys reads the key counter
+func (l *Local) loadKeys() (localKeys, error) {
+	var keys localKeys
+	data, err := os.ReadFile(l.keysPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return keys, nil
+		}
+		return keys, fmt.Errorf("failed to read local keys: %w", err)
+	}
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return keys, fmt.Errorf("failed to parse local keys: %w", err)
+	}
+	return keys, nil
+}
+
+// saveKeys writes the key counter
+func (l *Local) saveKeys(keys localKeys) error {
+	if err := os.MkdirAll(l.dataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal local keys: %w", err)
+	}
+	if err := os.WriteFile(l.keysPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write local keys: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package tracker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lunchboxsushi/jai/internal/markdown"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// writeTickets writes tickets to a markdown file in the tickets directory, the way the
+// commands do after a backend assigns their keys
+func writeTickets(t *testing.T, dataDir, name string, tickets ...types.Ticket) {
+	t.Helper()
+	path := filepath.Join(dataDir, "tickets", name)
+	if err := markdown.NewParser(dataDir).WriteFile(path, tickets); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalCreateTicket(t *testing.T) {
+	dataDir := t.TempDir()
+	local := NewLocal(dataDir, "obs")
+
+	first, err := local.CreateTicket(&types.Ticket{Type: types.TicketTypeEpic, Title: "Tracing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Key != "OBS-1" || first.ID != "1" || first.Status != defaultLocalStatus {
+		t.Errorf("first ticket = %s (ID %s, %q), want OBS-1 (ID 1, %q)", first.Key, first.ID, first.Status, defaultLocalStatus)
+	}
+
+	second, err := local.CreateTicket(&types.Ticket{Type: types.TicketTypeTask, Title: "Add spans", Status: "In Progress"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Key != "OBS-2" || second.Status != "In Progress" {
+		t.Errorf("second ticket = %s (%q), want OBS-2 (%q)", second.Key, second.Status, "In Progress")
+	}
+
+	if _, err := os.Stat(filepath.Join(dataDir, "local_keys.json")); err != nil {
+		t.Errorf("key counter not saved: %v", err)
+	}
+}
+
+func TestLocalCreateTicketDefaultPrefix(t *testing.T) {
+	created, err := NewLocal(t.TempDir(), "").CreateTicket(&types.Ticket{Title: "Inbox item"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Key != DefaultLocalPrefix+"-1" {
+		t.Errorf("key = %s, want %s-1", created.Key, DefaultLocalPrefix)
+	}
+}
+
+func TestLocalCreateTicketAfterLosingKeys(t *testing.T) {
+	dataDir := t.TempDir()
+	writeTickets(t, dataDir, "OBS-7-tracing.md",
+		types.Ticket{Type: types.TicketTypeEpic, Key: "OBS-7", Title: "Tracing", Status: "To Do"},
+		types.Ticket{Type: types.TicketTypeTask, Key: "OBS-12", Title: "Add spans", Status: "To Do"},
+		// Keys with another prefix don't count
+		types.Ticket{Type: types.TicketTypeTask, Key: "SRE-40", Title: "Dashboards", Status: "To Do"},
+	)
+
+	// No local_keys.json: the counter starts over, but keys already in use are skipped
+	created, err := NewLocal(dataDir, "OBS").CreateTicket(&types.Ticket{Title: "Sample traces"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Key != "OBS-13" {
+		t.Errorf("key = %s, want OBS-13", created.Key)
+	}
+}
+
+func TestLocalSearchTickets(t *testing.T) {
+	dataDir := t.TempDir()
+	writeTickets(t, dataDir, "OBS-1-tracing.md",
+		types.Ticket{Type: types.TicketTypeEpic, Key: "OBS-1", Title: "Tracing", Status: "In Progress"},
+		types.Ticket{Type: types.TicketTypeTask, Key: "OBS-2", Title: "Add spans", Status: "To Do", Labels: []string{"backend"}},
+		types.Ticket{Type: types.TicketTypeTask, Key: "OBS-3", Title: "Sample traces", Status: "Done", Labels: []string{"backend"}},
+	)
+	local := NewLocal(dataDir, "OBS")
+
+	tests := []struct {
+		query string
+		keys  []string
+	}{
+		{"", []string{"OBS-1", "OBS-2", "OBS-3"}},
+		{"SPANS", []string{"OBS-2"}},
+		{"backend", []string{"OBS-2", "OBS-3"}},
+		{"backend done", []string{"OBS-3"}},
+		{"epic", []string{"OBS-1"}},
+		{"obs-3", []string{"OBS-3"}},
+		{"metrics", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			tickets, err := local.SearchTickets(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var keys []string
+			for _, ticket := range tickets {
+				keys = append(keys, ticket.Key)
+			}
+			if len(keys) != len(tt.keys) {
+				t.Fatalf("SearchTickets(%q) = %v, want %v", tt.query, keys, tt.keys)
+			}
+			for i := range keys {
+				if keys[i] != tt.keys[i] {
+					t.Fatalf("SearchTickets(%q) = %v, want %v", tt.query, keys, tt.keys)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalSearchTicketsWithoutTicketsDir(t *testing.T) {
+	tickets, err := NewLocal(t.TempDir(), "OBS").SearchTickets("")
+	if err != nil || len(tickets) != 0 {
+		t.Errorf("SearchTickets in an empty data directory = %v, %v; want no tickets", tickets, err)
+	}
+}
+
+func TestLocalTransition(t *testing.T) {
+	dataDir := t.TempDir()
+	writeTickets(t, dataDir, "OBS-1-tracing.md",
+		types.Ticket{Type: types.TicketTypeEpic, Key: "OBS-1", Title: "Tracing", Status: "To Do"},
+		types.Ticket{Type: types.TicketTypeTask, Key: "OBS-2", Title: "Add spans", Status: "To Do"},
+	)
+	local := NewLocal(dataDir, "OBS")
+
+	// Local tickets have no workflow, so any status is taken as given
+	status, err := local.Transition("obs-2", "  Waiting on review ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != "Waiting on review" {
+		t.Errorf("Transition returned %q, want %q", status, "Waiting on review")
+	}
+
+	task, err := local.GetTicket("OBS-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != "Waiting on review" {
+		t.Errorf("OBS-2 status = %q, want %q", task.Status, "Waiting on review")
+	}
+	epic, err := local.GetTicket("OBS-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if epic.Status != "To Do" {
+		t.Errorf("OBS-1 status = %q, want it untouched", epic.Status)
+	}
+
+	if _, err := local.Transition("OBS-2", " "); err == nil {
+		t.Error("Transition to an empty status succeeded")
+	}
+	if _, err := local.Transition("OBS-9", "Done"); err == nil {
+		t.Error("Transition of an unknown key succeeded")
+	}
+}
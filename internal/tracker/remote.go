@@ -0,0 +1,57 @@
+package tracker
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// Statuses reported by trackers whose tickets are only open or closed
+const (
+	StatusOpen   = "Open"
+	StatusClosed = "Closed"
+)
+
+// DateFormat is the layout of due dates in the trackers' APIs
+const DateFormat = "2006-01-02"
+
+// Description returns the markdown sent as a ticket's description: the one pulled or
+// written earlier, or else the ticket's raw content
+func Description(ticket *types.Ticket) string {
+	if ticket.Description != "" {
+		return ticket.Description
+	}
+	return strings.TrimSpace(ticket.RawContent)
+}
+
+// KeyNumber returns the number of a key with the given prefix, e.g. 12 for GH-12
+func KeyNumber(key, prefix string) (int, bool) {
+	keyPrefix, number, ok := strings.Cut(strings.ToUpper(key), "-")
+	if !ok || keyPrefix != prefix {
+		return 0, false
+	}
+	n, err := strconv.Atoi(number)
+	return n, err == nil
+}
+
+// ParseDate parses a due date in DateFormat, returning nil if it is unset or malformed
+func ParseDate(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	date, err := time.Parse(DateFormat, value)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
+
+// OpenClosedStatus maps the "open" or "closed" state of a ticket to StatusOpen or StatusClosed
+func OpenClosedStatus(state string) string {
+	if state == "closed" {
+		return StatusClosed
+	}
+	return StatusOpen
+}
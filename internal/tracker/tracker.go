@@ -0,0 +1,52 @@
+package tracker
+
+import (
+	"strings"
+
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+// Tracker types selected with tracker.type in the config
+const (
+	TypeJira   = "jira"
+	TypeLocal  = "local"
+	TypeGitHub = "github"
+	TypeGitLab = "gitlab"
+	TypeLinear = "linear"
+)
+
+// Backend is an issue tracker that tickets are created in and read back from. The
+// markdown files stay the source of truth locally; a backend only holds the shared copy.
+type Backend interface {
+	// CreateTicket creates a ticket and returns it with the key and ID the tracker assigned
+	CreateTicket(ticket *types.Ticket) (*types.Ticket, error)
+
+	// GetTicket retrieves a ticket by key
+	GetTicket(key string) (*types.Ticket, error)
+
+	// UpdateTicket pushes a ticket's title, description and fields to the tracker
+	UpdateTicket(ticket *types.Ticket) error
+
+	// SearchTickets returns the tickets matching a query in the tracker's own syntax
+	SearchTickets(query string) ([]*types.Ticket, error)
+
+	// Transition moves a ticket to the status best matching the given name and returns the
+	// status it ended up in
+	Transition(key, status string) (string, error)
+}
+
+// NormalizeStatus lowercases a status name and strips separators so "in-progress" matches "In Progress"
+func NormalizeStatus(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(status)
+}
+
+// IsClosedStatus reports whether a status name means the work is finished, for trackers
+// that only know whether a ticket is open or closed
+func IsClosedStatus(status string) bool {
+	switch NormalizeStatus(status) {
+	case "closed", "close", "done", "complete", "completed", "resolved", "fixed":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package tracker
+
+import (
+	"testing"
+
+	"github.com/lunchboxsushi/jai/internal/markdown"
+	"github.com/lunchboxsushi/jai/internal/types"
+)
+
+func TestNormalizeStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{"In Progress", "inprogress"},
+		{"in-progress", "inprogress"},
+		{" IN_PROGRESS ", "inprogress"},
+		{"To Do", "todo"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeStatus(tt.status); got != tt.want {
+			t.Errorf("NormalizeStatus(%q) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestIsClosedStatus(t *testing.T) {
+	for _, status := range []string{"Done", "closed", "Resolved", " COMPLETED "} {
+		if !IsClosedStatus(status) {
+			t.Errorf("IsClosedStatus(%q) = false, want true", status)
+		}
+	}
+	for _, status := range []string{"To Do", "In Progress", "Reopened", ""} {
+		if IsClosedStatus(status) {
+			t.Errorf("IsClosedStatus(%q) = true, want false", status)
+		}
+	}
+}
+
+// TestBackendLifecycle drives a backend only through the Backend interface: create a
+// ticket, store it the way the commands do, then read, update, find and transition it
+func TestBackendLifecycle(t *testing.T) {
+	dataDir := t.TempDir()
+	var backend Backend = NewLocal(dataDir, "OBS")
+
+	created, err := backend.CreateTicket(&types.Ticket{Type: types.TicketTypeTask, Title: "Add spans"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Key == "" {
+		t.Fatal("CreateTicket returned no key")
+	}
+	writeTickets(t, dataDir, created.Key+"-add-spans.md", *created)
+
+	got, err := backend.GetTicket(created.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Titles are read back with the key from the header
+	if title := markdown.NewParser(dataDir).RemoveJiraKey(got.Title); title != "Add spans" || got.Type != types.TicketTypeTask {
+		t.Errorf("GetTicket = %q (%s), want %q (%s)", title, got.Type, "Add spans", types.TicketTypeTask)
+	}
+
+	got.Priority = "High"
+	got.Labels = []string{"tracing"}
+	if err := backend.UpdateTicket(got); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := backend.SearchTickets("tracing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Key != created.Key || found[0].Priority != "High" {
+		t.Fatalf("SearchTickets after update = %+v, want %s with priority High", found, created.Key)
+	}
+
+	status, err := backend.Transition(created.Key, "Done")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = backend.GetTicket(created.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != "Done" || got.Status != "Done" {
+		t.Errorf("after Transition: returned %q, stored %q; want Done", status, got.Status)
+	}
+
+	if _, err := backend.GetTicket("OBS-404"); err == nil {
+		t.Error("GetTicket of an unknown key succeeded")
+	}
+}
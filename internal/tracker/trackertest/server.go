@@ -0,0 +1,55 @@
+// Package trackertest provides a recording HTTP server for testing the tracker backends
+package trackertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Request is a call received by the test server
+type Request struct {
+	Method string
+	Path   string // Escaped, so encoded slashes in project paths stay visible
+	Query  url.Values
+	Header http.Header
+	Body   map[string]interface{}
+}
+
+// GraphQL returns the query and variables of a GraphQL request, or "" and nil for another
+// request
+func (r Request) GraphQL() (string, map[string]interface{}) {
+	query, _ := r.Body["query"].(string)
+	variables, _ := r.Body["variables"].(map[string]interface{})
+	return query, variables
+}
+
+// NewServer starts a server that records every request and answers it with handler. It
+// returns the server's URL and the requests received so far; the server is closed when the
+// test ends.
+func NewServer(t *testing.T, handler func(w http.ResponseWriter, r Request)) (string, *[]Request) {
+	t.Helper()
+	var requests []Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := Request{Method: r.Method, Path: r.URL.EscapedPath(), Query: r.URL.Query(), Header: r.Header}
+		if r.Body != nil && r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+				t.Errorf("%s %s: invalid JSON body: %v", r.Method, r.URL.Path, err)
+			}
+		}
+		requests = append(requests, req)
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, req)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, &requests
+}
+
+// Reply writes a JSON response
+func Reply(w http.ResponseWriter, status int, body string) {
+	w.WriteHeader(status)
+	fmt.Fprint(w, body)
+}
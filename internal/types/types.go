@@ -33,12 +33,40 @@ type Ticket struct {
 	Created      time.Time              `json:"created,omitempty"`
 	Updated      time.Time              `json:"updated,omitempty"`
 	DueDate      *time.Time             `json:"due_date,omitempty"`
+	Sprint       string                 `json:"sprint,omitempty"`
 	ParentKey    string                 `json:"parent_key,omitempty"`
 	EpicKey      string                 `json:"epic_key,omitempty"`
+	Project      string                 `json:"project,omitempty"` // Jira project, when not the profile's default
+	Profile      string                 `json:"profile,omitempty"` // Config profile the ticket was created under
 	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+	Comments     []Comment              `json:"comments,omitempty"`
+	Links        []Link                 `json:"links,omitempty"`
+	Attachments  []Attachment           `json:"attachments,omitempty"`
 	LineNumber   int                    `json:"line_number,omitempty"` // Position in markdown file
 }
 
+// Link is an issue link to another ticket, as read from this ticket's side
+type Link struct {
+	Relation string `json:"relation"`         // e.g. "blocks", "is blocked by", "relates to"
+	Key      string `json:"key"`              // The linked ticket
+	Status   string `json:"status,omitempty"` // Status of the linked ticket when last synced
+}
+
+// Attachment is a local file referenced from a ticket's description that has been
+// uploaded to Jira
+type Attachment struct {
+	ID   string `json:"id"`   // Jira attachment ID
+	Path string `json:"path"` // Link target the file is referenced by, e.g. "./img/trace.png"
+}
+
+// Comment represents a comment on a Jira ticket
+type Comment struct {
+	ID      string    `json:"id,omitempty"`
+	Author  string    `json:"author"`
+	Created time.Time `json:"created"`
+	Body    string    `json:"body"`
+}
+
 // TicketType represents the type of Jira ticket
 type TicketType string
 
@@ -51,13 +79,50 @@ const (
 // Config represents the application configuration
 type Config struct {
 	Jira struct {
-		URL           string `yaml:"url" json:"url"`
-		Username      string `yaml:"username" json:"username"`
-		Token         string `yaml:"token" json:"token"`
-		Project       string `yaml:"project" json:"project"`
-		EpicLinkField string `yaml:"epic_link_field" json:"epic_link_field"`
+		URL           string            `yaml:"url" json:"url"`
+		Username      string            `yaml:"username" json:"username"`
+		Token         string            `yaml:"token" json:"token"`
+		Project       string            `yaml:"project" json:"project"`
+		EpicLinkField string            `yaml:"epic_link_field" json:"epic_link_field"`
+		PageSize      int               `yaml:"page_size" json:"page_size"`
+		ProjectStyle  string            `yaml:"project_style" json:"project_style"` // "auto", "company" or "team"
+		APIVersion    string            `yaml:"api_version" json:"api_version"`     // "2" (wiki markup) or "3" (ADF)
+		Auth          string            `yaml:"auth" json:"auth"`                   // "basic", "bearer" or "oauth"
+		Timeout       int               `yaml:"timeout" json:"timeout"`             // Seconds per request attempt, 0 for none
+		MaxRetries    int               `yaml:"max_retries" json:"max_retries"`
+		BoardID       int               `yaml:"board_id" json:"board_id"`           // Agile board used for sprints
+		CustomFields  map[string]string `yaml:"custom_fields" json:"custom_fields"` // Friendly name → customfield ID
+		OAuth         struct {
+			TokenFile    string `yaml:"token_file" json:"token_file"`
+			ClientID     string `yaml:"client_id" json:"client_id"`
+			ClientSecret string `yaml:"client_secret" json:"client_secret"`
+			CloudID      string `yaml:"cloud_id" json:"cloud_id"`
+		} `yaml:"oauth" json:"oauth"`
 	} `yaml:"jira" json:"jira"`
 
+	Tracker struct {
+		Type  string `yaml:"type" json:"type"` // "jira" (default), "local", "github", "gitlab" or "linear"
+		Local struct {
+			Prefix string `yaml:"prefix" json:"prefix"` // Project part of local keys, e.g. LOCAL
+		} `yaml:"local" json:"local"`
+		GitHub struct {
+			URL   string `yaml:"url" json:"url"`     // API base URL, for GitHub Enterprise
+			Repo  string `yaml:"repo" json:"repo"`   // owner/name
+			Token string `yaml:"token" json:"token"` // From JAI_GITHUB_TOKEN
+		} `yaml:"github" json:"github"`
+		GitLab struct {
+			URL     string `yaml:"url" json:"url"`         // Instance URL, e.g. https://gitlab.com
+			Project string `yaml:"project" json:"project"` // Project path (group/name) or ID issues are created in
+			Group   string `yaml:"group" json:"group"`     // Group path or ID epics are created in
+			Token   string `yaml:"token" json:"token"`     // From JAI_GITLAB_TOKEN
+		} `yaml:"gitlab" json:"gitlab"`
+		Linear struct {
+			URL   string `yaml:"url" json:"url"`     // GraphQL endpoint
+			Team  string `yaml:"team" json:"team"`   // Team key, e.g. ENG
+			Token string `yaml:"token" json:"token"` // From JAI_LINEAR_TOKEN
+		} `yaml:"linear" json:"linear"`
+	} `yaml:"tracker" json:"tracker"`
+
 	AI struct {
 		Provider  string `yaml:"provider" json:"provider"` // "openai", "anthropic", etc.
 		APIKey    string `yaml:"api_key" json:"api_key"`
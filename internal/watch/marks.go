@@ -0,0 +1,85 @@
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultProfile names the top-level jira settings in the marks file
+const defaultProfile = "default"
+
+// Marks keeps the time of the last successful poll per profile, so 'jai watch' only asks
+// Jira for tickets updated since then, even across restarts
+type Marks struct {
+	marksPath string
+	marks     map[string]time.Time
+}
+
+// NewMarks creates a new high-water mark store in the data directory
+func NewMarks(dataDir string) *Marks {
+	return &Marks{
+		marksPath: filepath.Join(dataDir, "watch.json"),
+		marks:     make(map[string]time.Time),
+	}
+}
+
+// Load loads the marks from disk
+func (m *Marks) Load() error {
+	data, err := os.ReadFile(m.marksPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Never polled yet
+			m.marks = make(map[string]time.Time)
+			return nil
+		}
+		return fmt.Errorf("failed to read watch marks: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &m.marks); err != nil {
+		return fmt.Errorf("failed to parse watch marks: %w", err)
+	}
+	if m.marks == nil {
+		m.marks = make(map[string]time.Time)
+	}
+
+	return nil
+}
+
+// Save saves the marks to disk
+func (m *Marks) Save() error {
+	// Ensure directory exists
+	dir := filepath.Dir(m.marksPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create watch directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(m.marks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal watch marks: %w", err)
+	}
+
+	if err := os.WriteFile(m.marksPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write watch marks: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the time of the last successful poll for a profile, or the zero time
+func (m *Marks) Get(profile string) time.Time {
+	if profile == "" {
+		profile = defaultProfile
+	}
+	return m.marks[profile]
+}
+
+// Set records a successful poll for a profile
+func (m *Marks) Set(profile string, polled time.Time) {
+	if profile == "" {
+		profile = defaultProfile
+	}
+	m.marks[profile] = polled
+}
@@ -0,0 +1,136 @@
+package worklog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Entry is a single block of time logged against a ticket
+type Entry struct {
+	ID        string        `json:"id"`
+	Key       string        `json:"key"`
+	EpicKey   string        `json:"epic_key,omitempty"`
+	Started   time.Time     `json:"started"`
+	Duration  time.Duration `json:"duration"`
+	Comment   string        `json:"comment,omitempty"`
+	WorklogID string        `json:"worklog_id,omitempty"` // Jira worklog ID once posted
+	Error     string        `json:"error,omitempty"`      // Last error seen while posting
+}
+
+// Synced reports whether the entry has been posted to Jira
+func (e *Entry) Synced() bool {
+	return e.WorklogID != ""
+}
+
+// Ledger keeps every logged entry on disk so time can be logged offline
+// and flushed to Jira later
+type Ledger struct {
+	ledgerPath string
+	entries    []*Entry
+}
+
+// NewLedger creates a new worklog ledger
+func NewLedger(dataDir string) *Ledger {
+	return &Ledger{
+		ledgerPath: filepath.Join(dataDir, "worklog.json"),
+	}
+}
+
+// Load loads the ledger from disk
+func (l *Ledger) Load() error {
+	data, err := os.ReadFile(l.ledgerPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// No ledger yet, start empty
+			l.entries = nil
+			return nil
+		}
+		return fmt.Errorf("failed to read worklog ledger: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &l.entries); err != nil {
+		return fmt.Errorf("failed to parse worklog ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Save saves the ledger to disk
+func (l *Ledger) Save() error {
+	// Ensure directory exists
+	dir := filepath.Dir(l.ledgerPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create ledger directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(l.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal worklog ledger: %w", err)
+	}
+
+	if err := os.WriteFile(l.ledgerPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write worklog ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Add records a new entry and returns it
+func (l *Ledger) Add(key, epicKey string, started time.Time, duration time.Duration, comment string) *Entry {
+	entry := &Entry{
+		ID:       fmt.Sprintf("%d", time.Now().UnixNano()),
+		Key:      key,
+		EpicKey:  epicKey,
+		Started:  started,
+		Duration: duration,
+		Comment:  comment,
+	}
+	l.entries = append(l.entries, entry)
+	return entry
+}
+
+// Pending returns the entries that have not been posted to Jira yet
+func (l *Ledger) Pending() []*Entry {
+	var pending []*Entry
+	for _, entry := range l.entries {
+		if !entry.Synced() {
+			pending = append(pending, entry)
+		}
+	}
+	return pending
+}
+
+// Between returns the entries started in [from, to), oldest first
+func (l *Ledger) Between(from, to time.Time) []*Entry {
+	var entries []*Entry
+	for _, entry := range l.entries {
+		if !entry.Started.Before(from) && entry.Started.Before(to) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Started.Before(entries[j].Started)
+	})
+	return entries
+}
+
+// RenameKey moves entries logged against a ticket's old key, e.g. a placeholder used
+// while the ticket was waiting to be created, over to its new key
+func (l *Ledger) RenameKey(oldKey, newKey string) bool {
+	renamed := false
+	for _, entry := range l.entries {
+		if entry.Key == oldKey {
+			entry.Key = newKey
+			renamed = true
+		}
+		if entry.EpicKey == oldKey {
+			entry.EpicKey = newKey
+			renamed = true
+		}
+	}
+	return renamed
+}